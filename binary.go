@@ -0,0 +1,413 @@
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// The binary format of a set, used by MarshalBinary and GobEncode of every
+// implementation except RoaringBitmap, which uses the portable Roaring format,
+// is:
+//
+//	version  byte     binaryFormatVersion
+//	encoding byte     how the elements are encoded, one of the binary* constants
+//	count    uvarint  number of elements
+//	elements          count elements in the given encoding
+//
+// Integers are written in ascending order, each as the zigzag varint of its
+// difference from the previous one, so that dense sets of large numbers stay
+// small. Strings are written in ascending order, each as its uvarint length
+// followed by its bytes. Elements of any other type are sorted as described
+// for compareValues and encoded as a slice with encoding/gob. LinkedHashSet
+// writes its elements in insertion order instead of sorting them; the format
+// does not require any order.
+const binaryFormatVersion = 1
+
+// Element encodings of the binary format.
+const (
+	binarySigned byte = iota + 1
+	binaryUnsigned
+	binaryStrings
+	binaryGob
+)
+
+// binaryEncoding returns the element encoding used for type T.
+func binaryEncoding[T comparable]() byte {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binarySigned
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binaryUnsigned
+	case reflect.String:
+		return binaryStrings
+	}
+	return binaryGob
+}
+
+// marshalBinary encodes elems in the binary format. If sorted is true, elems
+// is sorted first, which makes the encoding of an unordered set deterministic.
+func marshalBinary[T comparable](elems []T, sorted bool) ([]byte, error) {
+	encoding := binaryEncoding[T]()
+	if sorted {
+		slices.SortFunc(elems, valueCompare[T]())
+	}
+
+	buf := []byte{binaryFormatVersion, encoding}
+	buf = binary.AppendUvarint(buf, uint64(len(elems)))
+	switch encoding {
+	case binarySigned, binaryUnsigned:
+		var prev uint64
+		for _, elem := range elems {
+			v := reflect.ValueOf(elem)
+			var x uint64
+			if encoding == binarySigned {
+				x = uint64(v.Int())
+			} else {
+				x = v.Uint()
+			}
+			// The difference wraps around for unsorted input, which the
+			// decoder undoes by wrapping around the same way.
+			buf = binary.AppendVarint(buf, int64(x-prev))
+			prev = x
+		}
+	case binaryStrings:
+		for _, elem := range elems {
+			s := reflect.ValueOf(elem).String()
+			buf = binary.AppendUvarint(buf, uint64(len(s)))
+			buf = append(buf, s...)
+		}
+	default:
+		var gobBuf bytes.Buffer
+		if err := gob.NewEncoder(&gobBuf).Encode(elems); err != nil {
+			return nil, err
+		}
+		buf = append(buf, gobBuf.Bytes()...)
+	}
+	return buf, nil
+}
+
+// binaryDecoder reads the binary format from a byte slice.
+type binaryDecoder struct {
+	data []byte
+}
+
+func invalidEncoding(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidEncoding, fmt.Sprintf(format, args...))
+}
+
+func (d *binaryDecoder) uvarint() (uint64, error) {
+	x, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, invalidEncoding("truncated or overlong varint")
+	}
+	d.data = d.data[n:]
+	return x, nil
+}
+
+func (d *binaryDecoder) varint() (int64, error) {
+	x, n := binary.Varint(d.data)
+	if n <= 0 {
+		return 0, invalidEncoding("truncated or overlong varint")
+	}
+	d.data = d.data[n:]
+	return x, nil
+}
+
+// unmarshalBinary decodes the elements of a set in the binary format.
+// Malformed input, or input encoding elements of another type, results in an
+// error wrapping ErrInvalidEncoding.
+func unmarshalBinary[T comparable](data []byte) ([]T, error) {
+	if len(data) < 2 {
+		return nil, invalidEncoding("missing header")
+	}
+	if data[0] != binaryFormatVersion {
+		return nil, invalidEncoding("unsupported version %d", data[0])
+	}
+	if want := binaryEncoding[T](); data[1] != want {
+		return nil, invalidEncoding("element encoding %d cannot be decoded into %v", data[1], reflect.TypeFor[T]())
+	}
+
+	d := &binaryDecoder{data: data[2:]}
+	count, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	// Every element takes at least one byte, which bounds the allocation
+	// below by the size of the input.
+	if count > uint64(len(d.data)) {
+		return nil, invalidEncoding("%d elements cannot fit in %d bytes", count, len(d.data))
+	}
+
+	var elems []T
+	switch data[1] {
+	case binarySigned, binaryUnsigned:
+		elems, err = decodeIntegers[T](d, count, data[1] == binarySigned)
+	case binaryStrings:
+		elems, err = decodeStrings[T](d, count)
+	default:
+		elems, err = decodeGob[T](d, count)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(d.data) != 0 {
+		return nil, invalidEncoding("%d trailing bytes", len(d.data))
+	}
+	return elems, nil
+}
+
+// unmarshalBinaryInto replaces the contents of s with the elements encoded in
+// data. On error, s is left unchanged.
+func unmarshalBinaryInto[T comparable](data []byte, s Set[T]) error {
+	elems, err := unmarshalBinary[T](data)
+	if err != nil {
+		return err
+	}
+	replaceElements(s, elems)
+	return nil
+}
+
+// decodeIntegers reads count integers written as zigzag varint differences.
+func decodeIntegers[T comparable](d *binaryDecoder, count uint64, signed bool) ([]T, error) {
+	elems := make([]T, count)
+	v := reflect.New(reflect.TypeFor[T]()).Elem()
+	var x uint64
+	for i := range elems {
+		delta, err := d.varint()
+		if err != nil {
+			return nil, err
+		}
+		x += uint64(delta)
+		if signed {
+			if v.OverflowInt(int64(x)) {
+				return nil, invalidEncoding("%d overflows %v", int64(x), v.Type())
+			}
+			v.SetInt(int64(x))
+		} else {
+			if v.OverflowUint(x) {
+				return nil, invalidEncoding("%d overflows %v", x, v.Type())
+			}
+			v.SetUint(x)
+		}
+		elems[i] = v.Interface().(T)
+	}
+	return elems, nil
+}
+
+// decodeStrings reads count length-prefixed strings.
+func decodeStrings[T comparable](d *binaryDecoder, count uint64) ([]T, error) {
+	elems := make([]T, count)
+	v := reflect.New(reflect.TypeFor[T]()).Elem()
+	for i := range elems {
+		n, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(len(d.data)) {
+			return nil, invalidEncoding("string of %d bytes exceeds the remaining %d", n, len(d.data))
+		}
+		v.SetString(string(d.data[:n]))
+		d.data = d.data[n:]
+		elems[i] = v.Interface().(T)
+	}
+	return elems, nil
+}
+
+// decodeGob reads a slice of count elements encoded with encoding/gob.
+func decodeGob[T comparable](d *binaryDecoder, count uint64) ([]T, error) {
+	reader := bytes.NewReader(d.data)
+	var elems []T
+	if err := gob.NewDecoder(reader).Decode(&elems); err != nil {
+		return nil, invalidEncoding("%v", err)
+	}
+	if uint64(len(elems)) != count {
+		return nil, invalidEncoding("%d elements, header says %d", len(elems), count)
+	}
+	d.data = d.data[len(d.data)-reader.Len():]
+	return elems, nil
+}
+
+// MarshalBinary encodes the set in a compact binary format. It implements
+// encoding.BinaryMarshaler.
+func (h *hashSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(h.ToSlice(), true)
+}
+
+// UnmarshalBinary replaces the contents of the set with the elements encoded
+// by MarshalBinary. It implements encoding.BinaryUnmarshaler.
+func (h *hashSet[T]) UnmarshalBinary(data []byte) error {
+	elems, err := unmarshalBinary[T](data)
+	if err != nil {
+		return err
+	}
+	h.elements = make(map[T]struct{}, len(elems))
+	for _, elem := range elems {
+		h.elements[elem] = struct{}{}
+	}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with the format of MarshalBinary.
+func (h *hashSet[T]) GobEncode() ([]byte, error) { return h.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the format of MarshalBinary.
+func (h *hashSet[T]) GobDecode(data []byte) error { return h.UnmarshalBinary(data) }
+
+// MarshalBinary encodes a snapshot of the set in a compact binary format. It
+// implements encoding.BinaryMarshaler.
+func (c *ConcurrentSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(c.ToSlice(), true)
+}
+
+// UnmarshalBinary atomically replaces the contents of the set with the
+// elements encoded by MarshalBinary. It implements encoding.BinaryUnmarshaler.
+func (c *ConcurrentSet[T]) UnmarshalBinary(data []byte) error {
+	elems, err := unmarshalBinary[T](data)
+	if err != nil {
+		return err
+	}
+	c.replace(elems)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with the format of MarshalBinary.
+func (c *ConcurrentSet[T]) GobEncode() ([]byte, error) { return c.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the format of MarshalBinary.
+func (c *ConcurrentSet[T]) GobDecode(data []byte) error { return c.UnmarshalBinary(data) }
+
+// MarshalBinary encodes a snapshot of the set in a compact binary format. It
+// implements encoding.BinaryMarshaler.
+func (s *shardedSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice(), true)
+}
+
+// UnmarshalBinary replaces the contents of the set with the elements encoded
+// by MarshalBinary. Like the other bulk operations, it is not atomic.
+func (s *shardedSet[T]) UnmarshalBinary(data []byte) error {
+	return unmarshalBinaryInto[T](data, s)
+}
+
+// GobEncode implements gob.GobEncoder with the format of MarshalBinary.
+func (s *shardedSet[T]) GobEncode() ([]byte, error) { return s.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the format of MarshalBinary.
+func (s *shardedSet[T]) GobDecode(data []byte) error { return s.UnmarshalBinary(data) }
+
+// MarshalBinary encodes the set in a compact binary format. It implements
+// encoding.BinaryMarshaler.
+func (s *SortedSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(s.ToSlice(), false)
+}
+
+// UnmarshalBinary replaces the contents of the set with the elements encoded
+// by MarshalBinary. It implements encoding.BinaryUnmarshaler.
+func (s *SortedSet[T]) UnmarshalBinary(data []byte) error {
+	return unmarshalBinaryInto[T](data, s)
+}
+
+// GobEncode implements gob.GobEncoder with the format of MarshalBinary.
+func (s *SortedSet[T]) GobEncode() ([]byte, error) { return s.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the format of MarshalBinary.
+func (s *SortedSet[T]) GobDecode(data []byte) error { return s.UnmarshalBinary(data) }
+
+// MarshalBinary encodes the set in a compact binary format, keeping the
+// insertion order. It implements encoding.BinaryMarshaler.
+func (l *LinkedHashSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(l.ToSlice(), false)
+}
+
+// UnmarshalBinary replaces the contents of the set with the elements encoded
+// by MarshalBinary, in their encoded order. It implements
+// encoding.BinaryUnmarshaler.
+func (l *LinkedHashSet[T]) UnmarshalBinary(data []byte) error {
+	return unmarshalBinaryInto[T](data, l)
+}
+
+// GobEncode implements gob.GobEncoder with the format of MarshalBinary.
+func (l *LinkedHashSet[T]) GobEncode() ([]byte, error) { return l.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the format of MarshalBinary.
+func (l *LinkedHashSet[T]) GobDecode(data []byte) error { return l.UnmarshalBinary(data) }
+
+// MarshalBinary encodes the set in a compact binary format. It implements
+// encoding.BinaryMarshaler.
+func (p *PersistentSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(p.ToSlice(), true)
+}
+
+// UnmarshalBinary replaces the receiver with a version holding the elements
+// encoded by MarshalBinary. Other versions are not affected.
+func (p *PersistentSet[T]) UnmarshalBinary(data []byte) error {
+	return unmarshalBinaryInto[T](data, p)
+}
+
+// GobEncode implements gob.GobEncoder with the format of MarshalBinary.
+func (p *PersistentSet[T]) GobEncode() ([]byte, error) { return p.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the format of MarshalBinary.
+func (p *PersistentSet[T]) GobDecode(data []byte) error { return p.UnmarshalBinary(data) }
+
+// MarshalBinary encodes the set in a compact binary format. It implements
+// encoding.BinaryMarshaler.
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	return marshalBinary(b.ToSlice(), false)
+}
+
+// UnmarshalBinary replaces the contents of the set with the elements encoded
+// by MarshalBinary. It implements encoding.BinaryUnmarshaler.
+// Elements too large for a BitSet, as described for MaxBitSetElement, result
+// in an error wrapping ErrTooLarge.
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	elems, err := unmarshalBinary[uint](data)
+	if err != nil {
+		return err
+	}
+	if err := checkDecodedElements[uint](b, elems, len(data)); err != nil {
+		return err
+	}
+	replaceElements[uint](b, elems)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with the format of MarshalBinary.
+func (b *BitSet) GobEncode() ([]byte, error) { return b.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the format of MarshalBinary.
+func (b *BitSet) GobDecode(data []byte) error { return b.UnmarshalBinary(data) }
+
+// GobEncode implements gob.GobEncoder with the portable Roaring format of
+// MarshalBinary.
+func (r *RoaringBitmap) GobEncode() ([]byte, error) { return r.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the portable Roaring format of
+// MarshalBinary.
+func (r *RoaringBitmap) GobDecode(data []byte) error { return r.UnmarshalBinary(data) }
+
+// MarshalBinary encodes the set in a compact binary format. It implements
+// encoding.BinaryMarshaler.
+func (f FrozenSet[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(f.ToSlice(), true)
+}
+
+// UnmarshalBinary sets f to the frozen set of the elements encoded by
+// MarshalBinary. It implements encoding.BinaryUnmarshaler.
+func (f *FrozenSet[T]) UnmarshalBinary(data []byte) error {
+	elems, err := unmarshalBinary[T](data)
+	if err != nil {
+		return err
+	}
+	*f = NewFrozenSet(elems...)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with the format of MarshalBinary.
+func (f FrozenSet[T]) GobEncode() ([]byte, error) { return f.MarshalBinary() }
+
+// GobDecode implements gob.GobDecoder with the format of MarshalBinary.
+func (f *FrozenSet[T]) GobDecode(data []byte) error { return f.UnmarshalBinary(data) }
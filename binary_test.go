@@ -0,0 +1,273 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+// binarySet is a set that can be encoded in the binary format.
+type binarySet[T comparable] interface {
+	Set[T]
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		elems := []int{math.MinInt, -7, 0, 3, 1 << 40, math.MaxInt}
+		testBinaryRoundTrip(t, elems, []func() binarySet[int]{
+			func() binarySet[int] { return NewHashSet[int]().(*hashSet[int]) },
+			func() binarySet[int] { return NewConcurrentSet[int]() },
+			func() binarySet[int] { return newShardedSet[int](4) },
+			func() binarySet[int] { return NewSortedSet[int]() },
+			func() binarySet[int] { return NewLinkedHashSet[int]() },
+			func() binarySet[int] { return NewPersistentSet[int]() },
+		})
+	})
+	t.Run("string", func(t *testing.T) {
+		elems := []string{"", "pear", "fig", "日本語", "a\x00b"}
+		testBinaryRoundTrip(t, elems, []func() binarySet[string]{
+			func() binarySet[string] { return NewHashSet[string]().(*hashSet[string]) },
+			func() binarySet[string] { return NewSortedSet[string]() },
+			func() binarySet[string] { return NewLinkedHashSet[string]() },
+		})
+	})
+	t.Run("Pair", func(t *testing.T) {
+		elems := []Pair2[string, int]{{"a", 1}, {"b", -2}, {"", 0}}
+		testBinaryRoundTrip(t, elems, []func() binarySet[Pair2[string, int]]{
+			func() binarySet[Pair2[string, int]] {
+				return NewHashSet[Pair2[string, int]]().(*hashSet[Pair2[string, int]])
+			},
+			func() binarySet[Pair2[string, int]] { return NewPersistentSet[Pair2[string, int]]() },
+		})
+	})
+	t.Run("uint", func(t *testing.T) {
+		testBinaryRoundTrip(t, []uint{0, 1, 64, 1000}, []func() binarySet[uint]{
+			func() binarySet[uint] { return NewBitSet() },
+			func() binarySet[uint] { return NewHashSet[uint]().(*hashSet[uint]) },
+		})
+	})
+}
+
+func testBinaryRoundTrip[T comparable](t *testing.T, elems []T, constructors []func() binarySet[T]) {
+	t.Helper()
+	for _, newSet := range constructors {
+		s := newSet()
+		s.InsertAll(elems...)
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%T.MarshalBinary() error = %v", s, err)
+		}
+		if again, _ := s.MarshalBinary(); !bytes.Equal(again, data) {
+			t.Errorf("%T.MarshalBinary() is not deterministic", s)
+		}
+
+		decoded := newSet()
+		decoded.Insert(elems[0]) // replaced by decoding
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%T.UnmarshalBinary() error = %v", decoded, err)
+		}
+		if !decoded.Equals(s) {
+			t.Errorf("%T round trip = %v, want %v", s, decoded, s)
+		}
+
+		empty, err := newSet().MarshalBinary()
+		if err != nil || decoded.UnmarshalBinary(empty) != nil || !decoded.IsEmpty() {
+			t.Errorf("%T round trip of the empty set failed: %v", s, err)
+		}
+	}
+}
+
+func TestBinaryFormat(t *testing.T) {
+	// A dense range of large integers takes about one byte per element.
+	s := NewSortedSet[int64]()
+	for i := range int64(1000) {
+		s.Insert(1<<50 + i)
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) > 1020 {
+		t.Errorf("MarshalBinary() of 1000 consecutive integers took %d bytes", len(data))
+	}
+
+	h := NewHashSet[string]()
+	h.InsertAll("b", "a")
+	got, _ := h.(*hashSet[string]).MarshalBinary()
+	want := []byte{binaryFormatVersion, binaryStrings, 2, 1, 'a', 1, 'b'}
+	if !bytes.Equal(got, want) {
+		t.Errorf("MarshalBinary() = %v, want %v", got, want)
+	}
+
+	// LinkedHashSet keeps its insertion order.
+	l := newLinkedHashSet(30, 10, 20)
+	data, _ = l.MarshalBinary()
+	decoded := NewLinkedHashSet[int]()
+	if err := decoded.UnmarshalBinary(data); err != nil || !slices.Equal(decoded.ToSlice(), []int{30, 10, 20}) {
+		t.Errorf("LinkedHashSet round trip = %v, %v, want [30 10 20]", decoded.ToSlice(), err)
+	}
+}
+
+func TestBinaryDecodingErrors(t *testing.T) {
+	ints, _ := newSortedSet(1, 2, 3).MarshalBinary()
+	strs, _ := NewLinkedHashSet[string]().MarshalBinary()
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, ErrInvalidEncoding},
+		{"unknown version", []byte{2, binarySigned, 0}, ErrInvalidEncoding},
+		{"wrong element type", strs, ErrInvalidEncoding},
+		{"truncated", ints[:len(ints)-1], ErrInvalidEncoding},
+		{"trailing bytes", append(slices.Clone(ints), 0), ErrInvalidEncoding},
+		{"count exceeds data", []byte{binaryFormatVersion, binarySigned, 100, 2}, ErrInvalidEncoding},
+		{"overflow", []byte{binaryFormatVersion, binarySigned, 1, 0x80, 0x02}, ErrInvalidEncoding},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSortedSet[int8]()
+			s.Insert(7)
+			if err := s.UnmarshalBinary(tt.data); !errors.Is(err, tt.err) {
+				t.Errorf("UnmarshalBinary() error = %v, want %v", err, tt.err)
+			}
+			if !s.Equals(newMockSet[int8](7)) {
+				t.Errorf("failed UnmarshalBinary() modified the set to %v", s)
+			}
+		})
+	}
+
+	// A few bytes cannot declare an element that needs a large bitmap.
+	for _, elem := range []int64{int64(MaxBitSetElement), int64(MaxBitSetElement) / 2, 1 << 21} {
+		huge := []byte{binaryFormatVersion, binaryUnsigned, 1}
+		huge = binary.AppendVarint(huge, elem)
+		if err := NewBitSet().UnmarshalBinary(huge); !errors.Is(err, ErrTooLarge) {
+			t.Errorf("BitSet.UnmarshalBinary() of element %d error = %v, want ErrTooLarge", elem, err)
+		}
+	}
+
+	limit := []byte{binaryFormatVersion, binaryUnsigned, 1}
+	limit = binary.AppendVarint(limit, int64(maxDecodedBitSetElement(len(limit)+4)))
+	b := NewBitSet()
+	if err := b.UnmarshalBinary(limit); err != nil || b.Cardinality() != 1 {
+		t.Errorf("BitSet.UnmarshalBinary() of the largest accepted element = %v, %v", b, err)
+	}
+}
+
+func TestGob(t *testing.T) {
+	type cache struct {
+		Tags   *SortedSet[string]
+		IDs    *ConcurrentSet[int]
+		Pairs  *PersistentSet[Pair2[string, int]]
+		Bitmap *RoaringBitmap
+		Frozen FrozenSet[int]
+	}
+
+	in := cache{
+		Tags:   newSortedSet("go", "sets"),
+		IDs:    NewConcurrentSet[int](),
+		Pairs:  NewPersistentSet[Pair2[string, int]]().With(Pair2[string, int]{"a", 1}),
+		Bitmap: NewRoaringBitmap(),
+		Frozen: NewFrozenSet(1, 2),
+	}
+	in.IDs.InsertAll(4, 5)
+	in.Bitmap.InsertAll(1, 1<<20)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out cache
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Tags.Equals(in.Tags) || !out.IDs.Equals(in.IDs) || !out.Pairs.Equals(in.Pairs) ||
+		!out.Bitmap.Equals(in.Bitmap) || out.Frozen != in.Frozen {
+		t.Errorf("gob round trip = %+v, want %+v", out, in)
+	}
+
+	// A set held in an interface is encoded once its type is registered.
+	gob.Register(NewHashSet[int]())
+	var s Set[int] = NewHashSet[int]()
+	s.InsertAll(1, 2, 3)
+	buf.Reset()
+	if err := gob.NewEncoder(&buf).Encode(&s); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Set[int]
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil || !decoded.Equals(s) {
+		t.Errorf("gob round trip through Set[int] = %v, %v, want %v", decoded, err, s)
+	}
+}
+
+func newSortedSet[T cmp.Ordered](elems ...T) *SortedSet[T] {
+	s := NewSortedSet[T]()
+	s.InsertAll(elems...)
+	return s
+}
+
+func FuzzUnmarshalBinaryInt(f *testing.F) {
+	seed, _ := newSortedSet[int64](-5, 0, 7, 1<<40).MarshalBinary()
+	f.Add(seed)
+	f.Add([]byte{binaryFormatVersion, binarySigned, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		testUnmarshalBinary(t, data, func() binarySet[int16] { return NewSortedSet[int16]() })
+		testUnmarshalBinary(t, data, func() binarySet[int] { return NewHashSet[int]().(*hashSet[int]) })
+	})
+}
+
+func FuzzUnmarshalBinaryBitSet(f *testing.F) {
+	seed, _ := newSortedSet[uint](0, 64, 1000).MarshalBinary()
+	f.Add(seed)
+	// A single element just below 2³², which would need a 512 MiB bitmap.
+	f.Add(binary.AppendVarint([]byte{binaryFormatVersion, binaryUnsigned, 1}, 1<<32-1))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		testUnmarshalBinary(t, data, func() binarySet[uint] { return NewBitSet() })
+	})
+}
+
+func FuzzUnmarshalBinaryString(f *testing.F) {
+	seed, _ := newSortedSet("", "a", "日本語").MarshalBinary()
+	f.Add(seed)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		testUnmarshalBinary(t, data, func() binarySet[string] { return NewLinkedHashSet[string]() })
+	})
+}
+
+func FuzzUnmarshalBinaryPair(f *testing.F) {
+	seed, _ := NewPersistentSet[Pair2[string, int]]().With(Pair2[string, int]{"a", 1}).MarshalBinary()
+	f.Add(seed)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		testUnmarshalBinary(t, data, func() binarySet[Pair2[string, int]] { return NewPersistentSet[Pair2[string, int]]() })
+	})
+}
+
+// testUnmarshalBinary checks that decoding data either fails with
+// ErrInvalidEncoding or yields a set that survives another round trip.
+func testUnmarshalBinary[T comparable](t *testing.T, data []byte, newSet func() binarySet[T]) {
+	s := newSet()
+	if err := s.UnmarshalBinary(data); err != nil {
+		if !errors.Is(err, ErrInvalidEncoding) && !errors.Is(err, ErrTooLarge) {
+			t.Errorf("UnmarshalBinary() error = %v, want it to wrap ErrInvalidEncoding or ErrTooLarge", err)
+		}
+		return
+	}
+	encoded, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() of a decoded set error = %v", err)
+	}
+	decoded := newSet()
+	if err := decoded.UnmarshalBinary(encoded); err != nil || !decoded.Equals(s) {
+		t.Errorf("round trip of decoded set %v = %v, %v", s, decoded, err)
+	}
+}
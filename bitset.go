@@ -0,0 +1,471 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+)
+
+// wordSize is the number of bits in each word of a BitSet.
+const wordSize = 64
+
+// BitSet implements the Set interface for non-negative integers using a dense
+// bitmap: element i is present when bit i%64 of word i/64 is set. For elements
+// drawn from a small range it needs one bit per possible element instead of
+// a map entry per element, and set algebra between two BitSets operates on
+// 64 elements at a time.
+//
+// The bitmap grows automatically to fit the largest inserted element, so
+// memory use is proportional to that element rather than to the cardinality.
+// BitSet is best suited to dense sets of small integers such as identifiers;
+// use RoaringBitmap for sparse ones. Elements may not exceed MaxBitSetElement.
+//
+// The zero value of a BitSet is an empty set ready to use.
+type BitSet struct {
+	words []uint64
+}
+
+// MaxBitSetElement is the largest element a BitSet can hold, for which its
+// bitmap takes 512 MiB. Insert, InsertAll, UnionWith and
+// SymmetricDifferenceWith panic if given a larger element, and Union and
+// SymmetricDifference return a hash set instead of a BitSet. Decoding a BitSet from JSON, the binary format, a set literal or an SQL
+// value fails with an error wrapping ErrTooLarge instead. Decoding also limits
+// the bitmap to 128 KiB plus 64 bytes per byte of input, so that a short
+// input cannot force a large allocation; use RoaringBitmap to decode sparse
+// sets of large elements.
+const MaxBitSetElement uint = 1<<32 - 1
+
+// maxDecodedBitSetElement returns the largest element accepted when decoding
+// a BitSet from n bytes of input.
+func maxDecodedBitSetElement(n int) uint {
+	limit := uint64(1)<<20 + uint64(n)*64*8
+	return uint(min(limit, uint64(MaxBitSetElement)))
+}
+
+// NewBitSet creates and returns a new empty bitset.
+func NewBitSet() *BitSet {
+	return &BitSet{}
+}
+
+// checkDecodedElements returns an error wrapping ErrTooLarge if s is a BitSet
+// and one of elems, decoded from n bytes of input, exceeds
+// maxDecodedBitSetElement. Other sets accept any element.
+func checkDecodedElements[T comparable](s Set[T], elems []T, n int) error {
+	if _, ok := any(s).(*BitSet); !ok {
+		return nil
+	}
+	limit := maxDecodedBitSetElement(n)
+	for _, elem := range any(elems).([]uint) {
+		if elem > limit {
+			return fmt.Errorf("%w: BitSet element %d exceeds the limit of %d for %d bytes of input", ErrTooLarge, elem, limit, n)
+		}
+	}
+	return nil
+}
+
+func (b *BitSet) emptyLike() Set[uint] {
+	return NewBitSet()
+}
+
+// wordIndex returns the index of the word holding elem and the mask of its bit.
+func wordIndex(elem uint) (int, uint64) {
+	return int(elem / wordSize), 1 << (elem % wordSize)
+}
+
+// trimmed returns the words of b without trailing zero words.
+func (b *BitSet) trimmed() []uint64 {
+	n := len(b.words)
+	for n > 0 && b.words[n-1] == 0 {
+		n--
+	}
+	return b.words[:n]
+}
+
+// Insert adds the element to the set. It panics if elem exceeds
+// MaxBitSetElement.
+func (b *BitSet) Insert(elem uint) {
+	if elem > MaxBitSetElement {
+		panic(fmt.Sprintf("set: BitSet element %d exceeds MaxBitSetElement", elem))
+	}
+	i, mask := wordIndex(elem)
+	if i >= len(b.words) {
+		b.words = append(b.words, make([]uint64, i+1-len(b.words))...)
+	}
+	b.words[i] |= mask
+}
+
+func (b *BitSet) Remove(elem uint) {
+	i, mask := wordIndex(elem)
+	if i < len(b.words) {
+		b.words[i] &^= mask
+	}
+}
+
+// InsertAll adds the elements to the set and returns the number of elements
+// added. It panics if one of elems exceeds MaxBitSetElement, after adding the
+// elements before it.
+func (b *BitSet) InsertAll(elems ...uint) int {
+	added := 0
+	for _, elem := range elems {
+		if !b.Contains(elem) {
+			b.Insert(elem)
+			added++
+		}
+	}
+	return added
+}
+
+func (b *BitSet) RemoveAll(elems ...uint) int {
+	removed := 0
+	for _, elem := range elems {
+		if b.Contains(elem) {
+			b.Remove(elem)
+			removed++
+		}
+	}
+	return removed
+}
+
+// RetainIf deletes every element for which keep returns false and returns the
+// number of elements deleted. keep is called in ascending order.
+func (b *BitSet) RetainIf(keep func(uint) bool) int {
+	return b.RemoveIf(func(elem uint) bool { return !keep(elem) })
+}
+
+// RemoveIf deletes every element for which remove returns true and returns the
+// number of elements deleted. remove is called in ascending order.
+func (b *BitSet) RemoveIf(remove func(uint) bool) int {
+	removed := 0
+	for i, w := range b.words {
+		for w != 0 {
+			bit := uint(bits.TrailingZeros64(w))
+			if remove(uint(i)*wordSize + bit) {
+				b.words[i] &^= 1 << bit
+				removed++
+			}
+			w &= w - 1
+		}
+	}
+	return removed
+}
+
+func (b *BitSet) Clear() int {
+	n := b.Cardinality()
+	clear(b.words)
+	b.words = b.words[:0]
+	return n
+}
+
+// Pop deletes the smallest element of the set and returns it.
+// The boolean is false if the set is empty.
+func (b *BitSet) Pop() (uint, bool) {
+	elem, ok := b.NextSet(0)
+	if ok {
+		b.Remove(elem)
+	}
+	return elem, ok
+}
+
+func (b *BitSet) Contains(elem uint) bool {
+	i, mask := wordIndex(elem)
+	return i < len(b.words) && b.words[i]&mask != 0
+}
+
+// Cardinality returns the number of elements in the set by counting the set
+// bits of every word.
+func (b *BitSet) Cardinality() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+func (b *BitSet) IsEmpty() bool {
+	return len(b.trimmed()) == 0
+}
+
+// NextSet returns the smallest element of the set that is greater than or
+// equal to i. The boolean is false if no such element exists.
+func (b *BitSet) NextSet(i uint) (uint, bool) {
+	w, _ := wordIndex(i)
+	if w >= len(b.words) {
+		return 0, false
+	}
+	// Discard the bits below i in its own word, then scan forward.
+	word := b.words[w] >> (i % wordSize)
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word)), true
+	}
+	for w++; w < len(b.words); w++ {
+		if b.words[w] != 0 {
+			return uint(w)*wordSize + uint(bits.TrailingZeros64(b.words[w])), true
+		}
+	}
+	return 0, false
+}
+
+// PrevSet returns the largest element of the set that is less than or equal
+// to i. The boolean is false if no such element exists.
+func (b *BitSet) PrevSet(i uint) (uint, bool) {
+	w, _ := wordIndex(i)
+	if w >= len(b.words) {
+		w = len(b.words) - 1
+		i = uint(len(b.words))*wordSize - 1
+	}
+	if w < 0 {
+		return 0, false
+	}
+	// Discard the bits above i in its own word, then scan backward.
+	word := b.words[w] << (wordSize - 1 - i%wordSize)
+	if word != 0 {
+		return i - uint(bits.LeadingZeros64(word)), true
+	}
+	for w--; w >= 0; w-- {
+		if b.words[w] != 0 {
+			return uint(w)*wordSize + wordSize - 1 - uint(bits.LeadingZeros64(b.words[w])), true
+		}
+	}
+	return 0, false
+}
+
+// All returns an iterator over the elements of the set in ascending order.
+func (b *BitSet) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				bit := uint(bits.TrailingZeros64(w))
+				if !yield(uint(i)*wordSize + bit) {
+					return
+				}
+				w &= w - 1 // clear the lowest set bit
+			}
+		}
+	}
+}
+
+// ToSlice returns a slice containing all elements in the set in ascending order.
+func (b *BitSet) ToSlice() []uint {
+	result := make([]uint, 0, b.Cardinality())
+	for elem := range b.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+// String returns a string representation of the set with its elements in
+// ascending order.
+func (b *BitSet) String() string {
+	return formatLiteral(b.ToSlice(), formatElement)
+}
+
+// Format implements fmt.Formatter, listing the elements in ascending order.
+func (b *BitSet) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, b.ToSlice(), "set.NewBitSet()", "*set.BitSet")
+}
+
+func (b *BitSet) Equals(other Set[uint]) bool {
+	o, ok := other.(*BitSet)
+	if !ok {
+		return equal(b, other)
+	}
+
+	bw, ow := b.trimmed(), o.trimmed()
+	if len(bw) != len(ow) {
+		return false
+	}
+	for i := range bw {
+		if bw[i] != ow[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *BitSet) IsSubsetOf(other Set[uint]) bool {
+	o, ok := other.(*BitSet)
+	if !ok {
+		return isSubset(b, other)
+	}
+
+	for i, w := range b.words {
+		var ow uint64
+		if i < len(o.words) {
+			ow = o.words[i]
+		}
+		if w&^ow != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *BitSet) IsSupersetOf(other Set[uint]) bool {
+	if o, ok := other.(*BitSet); ok {
+		return o.IsSubsetOf(b)
+	}
+	return isSubset(other, b)
+}
+
+func (b *BitSet) IsProperSubsetOf(other Set[uint]) bool {
+	return b.IsSubsetOf(other) && !b.Equals(other)
+}
+
+func (b *BitSet) IsProperSupersetOf(other Set[uint]) bool {
+	return b.IsSupersetOf(other) && !b.Equals(other)
+}
+
+// combine returns a new BitSet whose words are op applied to the corresponding
+// words of b and o, treating missing words as zero.
+func (b *BitSet) combine(o *BitSet, op func(x, y uint64) uint64) *BitSet {
+	n := max(len(b.words), len(o.words))
+	result := &BitSet{words: make([]uint64, n)}
+	for i := range n {
+		var x, y uint64
+		if i < len(b.words) {
+			x = b.words[i]
+		}
+		if i < len(o.words) {
+			y = o.words[i]
+		}
+		result.words[i] = op(x, y)
+	}
+	result.words = result.trimmed()
+	return result
+}
+
+// Union returns a new BitSet containing all elements that are in either this
+// set or the other set (**X** ∪ **Y**). Between two BitSets it is computed
+// word by word. If the other set has an element above MaxBitSetElement, the
+// result is a hash set.
+func (b *BitSet) Union(other Set[uint]) Set[uint] {
+	if o, ok := other.(*BitSet); ok {
+		return b.combine(o, func(x, y uint64) uint64 { return x | y })
+	}
+	return unionInto(b.emptyFor(other), b, other)
+}
+
+// Intersection returns a new BitSet containing all elements that are in both
+// this set and the other set (**X** ∩ **Y**). Between two BitSets it is
+// computed word by word.
+func (b *BitSet) Intersection(other Set[uint]) Set[uint] {
+	if o, ok := other.(*BitSet); ok {
+		return b.combine(o, func(x, y uint64) uint64 { return x & y })
+	}
+	return intersectionInto(NewBitSet(), b, other)
+}
+
+// Difference returns a new BitSet containing all elements that are in this set
+// but not in the other set (**X** \ **Y**). Between two BitSets it is computed
+// word by word.
+func (b *BitSet) Difference(other Set[uint]) Set[uint] {
+	if o, ok := other.(*BitSet); ok {
+		return b.combine(o, func(x, y uint64) uint64 { return x &^ y })
+	}
+	return differenceInto(NewBitSet(), b, other)
+}
+
+// SymmetricDifference returns a new BitSet containing all elements that are in
+// either this set or the other set, but not in both (**X** Δ **Y**). Between
+// two BitSets it is computed word by word. If the other set has an element
+// above MaxBitSetElement, the result is a hash set.
+func (b *BitSet) SymmetricDifference(other Set[uint]) Set[uint] {
+	if o, ok := other.(*BitSet); ok {
+		return b.combine(o, func(x, y uint64) uint64 { return x ^ y })
+	}
+	return symmetricDifferenceInto(b.emptyFor(other), b, other)
+}
+
+// emptyFor returns an empty BitSet if it can hold every element of other, and
+// an empty hash set otherwise.
+func (b *BitSet) emptyFor(other Set[uint]) Set[uint] {
+	if _, ok := largeElement(other); ok {
+		return NewHashSet[uint]()
+	}
+	return NewBitSet()
+}
+
+// largeElement returns an element of s above MaxBitSetElement. The boolean is
+// false if there is none.
+func largeElement(s Set[uint]) (uint, bool) {
+	for elem := range s.All() {
+		if elem > MaxBitSetElement {
+			return elem, true
+		}
+	}
+	return 0, false
+}
+
+// mustHold panics, before any change to b, if other has an element above
+// MaxBitSetElement.
+func (b *BitSet) mustHold(other Set[uint]) {
+	if elem, ok := largeElement(other); ok {
+		panic(fmt.Sprintf("set: BitSet element %d exceeds MaxBitSetElement", elem))
+	}
+}
+
+// combineInPlace sets every word of b to op applied to it and the
+// corresponding word of o, treating missing words as zero, and returns the
+// number of elements added or removed. b only grows if op can set bits that
+// are clear in b.
+func (b *BitSet) combineInPlace(o *BitSet, op func(x, y uint64) uint64) int {
+	if len(o.words) > len(b.words) && op(0, ^uint64(0)) != 0 {
+		b.words = append(b.words, make([]uint64, len(o.words)-len(b.words))...)
+	}
+	changed := 0
+	for i, x := range b.words {
+		var y uint64
+		if i < len(o.words) {
+			y = o.words[i]
+		}
+		b.words[i] = op(x, y)
+		changed += bits.OnesCount64(x ^ b.words[i])
+	}
+	b.words = b.trimmed()
+	return changed
+}
+
+// UnionWith adds the elements of the other set to this set in place
+// (**X** ← **X** ∪ **Y**) and returns the number of elements added. With
+// another BitSet it is computed word by word. It panics, leaving the set
+// unchanged, if the other set has an element above MaxBitSetElement.
+func (b *BitSet) UnionWith(other Set[uint]) int {
+	if o, ok := other.(*BitSet); ok {
+		return b.combineInPlace(o, func(x, y uint64) uint64 { return x | y })
+	}
+	b.mustHold(other)
+	return insertAll(b, other.All())
+}
+
+// IntersectWith deletes the elements of this set that are not in the other set
+// (**X** ← **X** ∩ **Y**) and returns the number of elements deleted. With
+// another BitSet it is computed word by word.
+func (b *BitSet) IntersectWith(other Set[uint]) int {
+	if o, ok := other.(*BitSet); ok {
+		return b.combineInPlace(o, func(x, y uint64) uint64 { return x & y })
+	}
+	return b.RetainIf(other.Contains)
+}
+
+// DifferenceWith deletes the elements of this set that are in the other set
+// (**X** ← **X** \ **Y**) and returns the number of elements deleted. With
+// another BitSet it is computed word by word.
+func (b *BitSet) DifferenceWith(other Set[uint]) int {
+	if o, ok := other.(*BitSet); ok {
+		return b.combineInPlace(o, func(x, y uint64) uint64 { return x &^ y })
+	}
+	return differenceWith(b, other)
+}
+
+// SymmetricDifferenceWith deletes the elements of this set that are in the
+// other set and adds those that are not (**X** ← **X** Δ **Y**). It returns the
+// number of elements added or deleted. With another BitSet it is computed word
+// by word. It panics, leaving the set unchanged, if the other set has an
+// element above MaxBitSetElement.
+func (b *BitSet) SymmetricDifferenceWith(other Set[uint]) int {
+	if o, ok := other.(*BitSet); ok {
+		return b.combineInPlace(o, func(x, y uint64) uint64 { return x ^ y })
+	}
+	b.mustHold(other)
+	return symmetricDifferenceWith(b, other)
+}
@@ -0,0 +1,300 @@
+package set
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBitSetBasicOperations(t *testing.T) {
+	var b BitSet // the zero value is ready to use
+	if !b.IsEmpty() || b.Cardinality() != 0 {
+		t.Error("zero BitSet should be empty")
+	}
+
+	for _, v := range []uint{0, 63, 64, 1000, 5} {
+		b.Insert(v)
+	}
+	b.Insert(63)
+
+	if b.Cardinality() != 5 {
+		t.Errorf("Cardinality() = %d, want 5", b.Cardinality())
+	}
+	for _, v := range []uint{0, 5, 63, 64, 1000} {
+		if !b.Contains(v) {
+			t.Errorf("Contains(%d) = false, want true", v)
+		}
+	}
+	for _, v := range []uint{1, 62, 65, 999, 1 << 20} {
+		if b.Contains(v) {
+			t.Errorf("Contains(%d) = true, want false", v)
+		}
+	}
+
+	if got, want := b.ToSlice(), []uint{0, 5, 63, 64, 1000}; !slices.Equal(got, want) {
+		t.Errorf("ToSlice() = %v, want %v", got, want)
+	}
+	if got, want := b.String(), "{0, 5, 63, 64, 1000}"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	b.Remove(1000)
+	b.Remove(1 << 20) // beyond the bitmap, must not grow or panic
+	if b.Contains(1000) || b.Cardinality() != 4 {
+		t.Errorf("Remove(1000) left %v", &b)
+	}
+	for _, v := range []uint{0, 5, 63, 64} {
+		b.Remove(v)
+	}
+	if !b.IsEmpty() || b.String() != "{}" {
+		t.Errorf("set should be empty, got %v", &b)
+	}
+}
+
+func TestBitSetScanning(t *testing.T) {
+	b := NewBitSet()
+	for _, v := range []uint{3, 64, 130, 200} {
+		b.Insert(v)
+	}
+
+	next := []struct {
+		from uint
+		want uint
+		ok   bool
+	}{
+		{0, 3, true},
+		{3, 3, true},
+		{4, 64, true},
+		{65, 130, true},
+		{131, 200, true},
+		{201, 0, false},
+		{10000, 0, false},
+	}
+	for _, tt := range next {
+		if got, ok := b.NextSet(tt.from); got != tt.want || ok != tt.ok {
+			t.Errorf("NextSet(%d) = %d, %v, want %d, %v", tt.from, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	prev := []struct {
+		from uint
+		want uint
+		ok   bool
+	}{
+		{10000, 200, true},
+		{200, 200, true},
+		{199, 130, true},
+		{129, 64, true},
+		{63, 3, true},
+		{2, 0, false},
+	}
+	for _, tt := range prev {
+		if got, ok := b.PrevSet(tt.from); got != tt.want || ok != tt.ok {
+			t.Errorf("PrevSet(%d) = %d, %v, want %d, %v", tt.from, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, ok := NewBitSet().PrevSet(5); ok {
+		t.Error("PrevSet on an empty BitSet should report false")
+	}
+}
+
+func TestBitSetOperations(t *testing.T) {
+	a := NewBitSet()
+	b := NewBitSet()
+	for _, v := range []uint{1, 2, 3, 4, 300} {
+		a.Insert(v)
+	}
+	for _, v := range []uint{3, 4, 5} {
+		b.Insert(v)
+	}
+
+	tests := []struct {
+		name     string
+		result   Set[uint]
+		expected []uint
+	}{
+		{"Union", a.Union(b), []uint{1, 2, 3, 4, 5, 300}},
+		{"Intersection", a.Intersection(b), []uint{3, 4}},
+		{"Difference", a.Difference(b), []uint{1, 2, 300}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []uint{1, 2, 5, 300}},
+		{"Union with mockSet", a.Union(newMockSet[uint](9)), []uint{1, 2, 3, 4, 9, 300}},
+		{"Intersection with mockSet", a.Intersection(newMockSet[uint](300, 7)), []uint{300}},
+		{"Difference with mockSet", a.Difference(newMockSet[uint](300)), []uint{1, 2, 3, 4}},
+		{"SymmetricDifference with mockSet", a.SymmetricDifference(newMockSet[uint](1, 9)), []uint{2, 3, 4, 9, 300}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs, ok := tt.result.(*BitSet)
+			if !ok {
+				t.Fatalf("%s returned %T, want *BitSet", tt.name, tt.result)
+			}
+			if got := bs.ToSlice(); !slices.Equal(got, tt.expected) {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+
+	// Sets with trailing empty words must still compare equal.
+	c := NewBitSet()
+	c.Insert(3)
+	c.Insert(4)
+	c.Insert(1000)
+	c.Remove(1000)
+	if !c.Equals(a.Intersection(b)) || !a.Intersection(b).Equals(c) {
+		t.Error("Equals should ignore trailing empty words")
+	}
+
+	if !c.IsProperSubsetOf(a) || !a.IsProperSupersetOf(c) || c.IsProperSubsetOf(c) {
+		t.Error("proper subset relations between BitSets are incorrect")
+	}
+	if !c.IsSubsetOf(newMockSet[uint](3, 4)) || !c.IsSupersetOf(newMockSet[uint](4)) || !c.Equals(newMockSet[uint](4, 3)) {
+		t.Error("relations with other implementations are incorrect")
+	}
+	if a.IsSubsetOf(b) || b.IsSupersetOf(a) {
+		t.Error("non-subsets reported as subsets")
+	}
+}
+
+func TestBitSetMatchesHashSet(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	b := NewBitSet()
+	h := NewHashSet[uint]()
+
+	for i := 0; i < 5000; i++ {
+		v := uint(r.IntN(2000))
+		if r.IntN(3) == 0 {
+			b.Remove(v)
+			h.Remove(v)
+		} else {
+			b.Insert(v)
+			h.Insert(v)
+		}
+	}
+
+	if !b.Equals(h) || !h.Equals(b) || b.Cardinality() != h.Cardinality() {
+		t.Fatalf("BitSet diverged from hashSet")
+	}
+}
+
+func BenchmarkBitSetVersusHashSet(b *testing.B) {
+	const universe = 1 << 20
+	sizes := []int{1000, 100000}
+
+	for _, size := range sizes {
+		r := rand.New(rand.NewPCG(5, 6))
+		elems := make([]uint, size)
+		for i := range elems {
+			elems[i] = uint(r.IntN(universe))
+		}
+
+		implementations := []struct {
+			name string
+			new  func() Set[uint]
+		}{
+			{"BitSet", func() Set[uint] { return NewBitSet() }},
+			{"HashSet", NewHashSet[uint]},
+		}
+
+		for _, impl := range implementations {
+			b.Run(fmt.Sprintf("%s/Insert/size=%d", impl.name, size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					s := impl.new()
+					for _, e := range elems {
+						s.Insert(e)
+					}
+				}
+			})
+
+			s1, s2 := impl.new(), impl.new()
+			for i, e := range elems {
+				s1.Insert(e)
+				if i%2 == 0 {
+					s2.Insert(e)
+				}
+			}
+
+			b.Run(fmt.Sprintf("%s/Contains/size=%d", impl.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_ = s1.Contains(elems[i%size])
+				}
+			})
+
+			b.Run(fmt.Sprintf("%s/Intersection/size=%d", impl.name, size), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = s1.Intersection(s2)
+				}
+			})
+
+			b.Run(fmt.Sprintf("%s/Cardinality/size=%d", impl.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_ = s1.Cardinality()
+				}
+			})
+		}
+	}
+}
+
+func TestBitSetMaxElement(t *testing.T) {
+	if math.MaxUint == MaxBitSetElement {
+		t.Skip("every uint is a valid BitSet element on this platform")
+	}
+	b := NewBitSet()
+	if b.Contains(MaxBitSetElement) || b.Contains(math.MaxUint) {
+		t.Error("Contains() of an element beyond the bitmap = true")
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "MaxBitSetElement") {
+			t.Errorf("Insert(MaxBitSetElement + 1) panic = %v, want a MaxBitSetElement panic", r)
+		}
+	}()
+	over := MaxBitSetElement
+	over++ // not a constant expression, which would overflow on 32-bit platforms
+	b.Insert(over)
+}
+
+func TestBitSetLargeOperands(t *testing.T) {
+	if math.MaxUint == MaxBitSetElement {
+		t.Skip("every uint is a valid BitSet element on this platform")
+	}
+	b := NewBitSet()
+	b.InsertAll(1, 2)
+	other := NewHashSet[uint]()
+	other.InsertAll(2, math.MaxUint)
+
+	union := b.Union(other)
+	if _, ok := union.(*BitSet); ok || !union.Equals(newMockSet[uint](1, 2, math.MaxUint)) {
+		t.Errorf("Union() = %T %v, want a hash set of {1, 2, MaxUint}", union, union)
+	}
+	symmetric := b.SymmetricDifference(other)
+	if _, ok := symmetric.(*BitSet); ok || !symmetric.Equals(newMockSet[uint](1, math.MaxUint)) {
+		t.Errorf("SymmetricDifference() = %T %v, want a hash set of {1, MaxUint}", symmetric, symmetric)
+	}
+	if _, ok := b.Union(newMockSet[uint](3)).(*BitSet); !ok {
+		t.Error("Union() with small elements did not return a BitSet")
+	}
+
+	for name, op := range map[string]func(){
+		"UnionWith":               func() { b.UnionWith(other) },
+		"SymmetricDifferenceWith": func() { b.SymmetricDifferenceWith(other) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "MaxBitSetElement") {
+					t.Errorf("%s() panic = %v, want a MaxBitSetElement panic", name, r)
+				}
+				if !slices.Equal(b.ToSlice(), []uint{1, 2}) {
+					t.Errorf("%s() modified the set to %v before panicking", name, b)
+				}
+			}()
+			op()
+		})
+	}
+}
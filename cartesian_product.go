@@ -1,6 +1,9 @@
 package set
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+)
 
 // Pair represents an ordered pair of elements for use in Cartesian products.
 type Pair[T comparable] struct {
@@ -10,7 +13,32 @@ type Pair[T comparable] struct {
 
 // String returns a string representation of the pair in the format "(First, Second)".
 func (p Pair[T]) String() string {
-	return fmt.Sprintf("(%v, %v)", p.First, p.Second)
+	return "(" + formatElement(p.First) + ", " + formatElement(p.Second) + ")"
+}
+
+// Pair2 is like Pair, but its two elements may have different types, as in the
+// result of CartesianProduct2.
+type Pair2[A, B comparable] struct {
+	First  A
+	Second B
+}
+
+// String returns a string representation of the pair in the format "(First, Second)".
+func (p Pair2[A, B]) String() string {
+	return "(" + formatElement(p.First) + ", " + formatElement(p.Second) + ")"
+}
+
+// Triple represents an ordered triple of elements for use in Cartesian products.
+type Triple[A, B, C comparable] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// String returns a string representation of the triple in the format
+// "(First, Second, Third)".
+func (t Triple[A, B, C]) String() string {
+	return "(" + formatElement(t.First) + ", " + formatElement(t.Second) + ", " + formatElement(t.Third) + ")"
 }
 
 // CartesianProduct returns a new set containing all possible ordered pairs
@@ -20,19 +48,115 @@ func (p Pair[T]) String() string {
 // For example, if A = {1, 2} and B = {3, 4}, then
 // CartesianProduct(A, B) = {(1, 3), (1, 4), (2, 3), (2, 4)}.
 func CartesianProduct[T comparable](s1, s2 Set[T]) Set[Pair[T]] {
-	result := NewHashSet[Pair[T]]()
+	result := &hashSet[Pair[T]]{
+		elements: make(map[Pair[T]]struct{}, productSizeHint(s1.Cardinality(), s2.Cardinality())),
+	}
+	for p := range CartesianProductSeq(s1, s2) {
+		result.elements[Pair[T](p)] = struct{}{}
+	}
+	return result
+}
+
+// CartesianProduct2 is like CartesianProduct, but the two sets may have
+// different element types, as in users × roles.
+func CartesianProduct2[A, B comparable](a Set[A], b Set[B]) Set[Pair2[A, B]] {
+	result := &hashSet[Pair2[A, B]]{
+		elements: make(map[Pair2[A, B]]struct{}, productSizeHint(a.Cardinality(), b.Cardinality())),
+	}
+	Insert(result, CartesianProductSeq(a, b))
+	return result
+}
 
-	s1Elems := s1.ToSlice()
-	s2Elems := s2.ToSlice()
+// maxProductSizeHint bounds the number of pairs for which the Cartesian
+// products preallocate space, so that a large product grows as it is built
+// instead of allocating all its memory up front.
+const maxProductSizeHint = 1 << 16
 
-	for _, elem1 := range s1Elems {
-		for _, elem2 := range s2Elems {
-			result.Insert(Pair[T]{
-				First:  elem1,
-				Second: elem2,
-			})
+// productSizeHint returns the number of pairs to preallocate for the product
+// of n and m elements, without overflowing.
+func productSizeHint(n, m int) int {
+	if m != 0 && n > maxProductSizeHint/m {
+		return maxProductSizeHint
+	}
+	return n * m
+}
+
+// CartesianProductWithLimit is like CartesianProduct2, but returns an error
+// wrapping ErrTooLarge instead of building a product of more than limit pairs.
+// Use CartesianProductSeq to visit the pairs of a large product one at a time.
+func CartesianProductWithLimit[A, B comparable](a Set[A], b Set[B], limit int) (Set[Pair2[A, B]], error) {
+	n, m := a.Cardinality(), b.Cardinality()
+	if m != 0 && n > limit/m {
+		return nil, fmt.Errorf("%w: Cartesian product of %d and %d elements exceeds the limit of %d", ErrTooLarge, n, m, limit)
+	}
+	return CartesianProduct2(a, b), nil
+}
+
+// CartesianProductSeq returns an iterator over the ordered pairs of A × B,
+// without building the product. The sets must not be modified during
+// iteration.
+func CartesianProductSeq[A, B comparable](a Set[A], b Set[B]) iter.Seq[Pair2[A, B]] {
+	return func(yield func(Pair2[A, B]) bool) {
+		for x := range a.All() {
+			for y := range b.All() {
+				if !yield(Pair2[A, B]{First: x, Second: y}) {
+					return
+				}
+			}
 		}
 	}
+}
 
+// CartesianProduct3 returns a new set containing all ordered triples (x, y, z)
+// where x is from a, y is from b and z is from c.
+// For sets A, B and C, it returns A × B × C = {(x, y, z) | x ∈ A, y ∈ B, z ∈ C}.
+func CartesianProduct3[A, B, C comparable](a Set[A], b Set[B], c Set[C]) Set[Triple[A, B, C]] {
+	result := NewHashSet[Triple[A, B, C]]()
+	for p := range CartesianProductSeq(a, b) {
+		for z := range c.All() {
+			result.Insert(Triple[A, B, C]{First: p.First, Second: p.Second, Third: z})
+		}
+	}
 	return result
 }
+
+// CartesianProductN returns an iterator over the n-tuples of the Cartesian
+// product S₁ × S₂ × … × Sₙ of the given sets. Every tuple is a new slice of
+// length n whose i-th element is from the i-th set. The product of no sets
+// holds a single empty tuple, and the product with an empty set holds none.
+//
+// Tuples are yielded in lexicographic order of the positions of their
+// elements, as the sets are visited when iteration starts.
+func CartesianProductN[T comparable](sets ...Set[T]) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		elems := make([][]T, len(sets))
+		for i, s := range sets {
+			elems[i] = s.ToSlice()
+			if len(elems[i]) == 0 {
+				return
+			}
+		}
+
+		// positions is an odometer over the sets, the last one turning fastest.
+		positions := make([]int, len(sets))
+		for {
+			tuple := make([]T, len(sets))
+			for i, p := range positions {
+				tuple[i] = elems[i][p]
+			}
+			if !yield(tuple) {
+				return
+			}
+
+			i := len(positions) - 1
+			for i >= 0 && positions[i] == len(elems[i])-1 {
+				positions[i] = 0
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			positions[i]++
+		}
+	}
+}
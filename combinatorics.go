@@ -0,0 +1,168 @@
+package set
+
+import (
+	"context"
+	"iter"
+	"math/big"
+	"slices"
+)
+
+// Combinations returns an iterator over the k-element subsets of s, the
+// k-combinations of its elements. It is PowerSetSeqOfSize without a context;
+// stop early by breaking out of the loop.
+//
+// For a set with n elements there are Binomial(n, k) combinations.
+func Combinations[T comparable](s Set[T], k int) iter.Seq[Set[T]] {
+	return PowerSetSeqOfSize(context.Background(), s, k)
+}
+
+// Permutations returns an iterator over all orderings of the elements of s.
+// Every permutation is a new slice holding each element of s exactly once.
+//
+// For a set with n elements there are Factorial(n) permutations, generated
+// with Heap's algorithm so that consecutive permutations differ by a swap.
+func Permutations[T comparable](s Set[T]) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		perm := s.ToSlice()
+		if !yield(slices.Clone(perm)) {
+			return
+		}
+
+		// counters[i] counts the swaps made so far with the prefix of length
+		// i+1, replacing the recursion of the original algorithm.
+		counters := make([]int, len(perm))
+		for i := 1; i < len(perm); {
+			if counters[i] >= i {
+				counters[i] = 0
+				i++
+				continue
+			}
+			if i%2 == 0 {
+				perm[0], perm[i] = perm[i], perm[0]
+			} else {
+				perm[counters[i]], perm[i] = perm[i], perm[counters[i]]
+			}
+			if !yield(slices.Clone(perm)) {
+				return
+			}
+			counters[i]++
+			i = 1
+		}
+	}
+}
+
+// Partitions returns an iterator over all partitions of s, the ways of
+// splitting s into non-empty, pairwise disjoint blocks whose union is s.
+// Every partition is a new slice of new sets.
+//
+// For a set with n elements there are Bell(n) partitions, of which
+// StirlingSecond(n, k) have exactly k blocks. The empty set has a single
+// partition with no blocks.
+func Partitions[T comparable](s Set[T]) iter.Seq[[]Set[T]] {
+	return func(yield func([]Set[T]) bool) {
+		elems := s.ToSlice()
+		n := len(elems)
+
+		// The partitions are enumerated as restricted growth strings: elems[i]
+		// is in block blocks[i], and every block number is at most one more
+		// than the largest before it. maxBefore[i] is that largest number.
+		blocks := make([]int, n)
+		maxBefore := make([]int, n)
+		for {
+			if !yield(partitionOf(elems, blocks)) {
+				return
+			}
+
+			// Increment the last position that can grow, resetting those after it.
+			i := n - 1
+			for i > 0 && blocks[i] > maxBefore[i] {
+				i--
+			}
+			if i <= 0 {
+				return
+			}
+			blocks[i]++
+			for j := i + 1; j < n; j++ {
+				blocks[j] = 0
+				maxBefore[j] = max(maxBefore[j-1], blocks[j-1])
+			}
+		}
+	}
+}
+
+// partitionOf returns the blocks of elems given by the block number of each.
+func partitionOf[T comparable](elems []T, blocks []int) []Set[T] {
+	var result []Set[T]
+	for i, b := range blocks {
+		if b == len(result) {
+			result = append(result, NewHashSet[T]())
+		}
+		result[b].Insert(elems[i])
+	}
+	return result
+}
+
+// Binomial returns the binomial coefficient n choose k, the number of
+// k-element subsets of a set with n elements. It is 0 unless 0 ≤ k ≤ n.
+func Binomial(n, k int) *big.Int {
+	if k < 0 || k > n {
+		return new(big.Int)
+	}
+	return new(big.Int).Binomial(int64(n), int64(k))
+}
+
+// Factorial returns n!, the number of permutations of a set with n elements.
+// It is 0 for negative n.
+func Factorial(n int) *big.Int {
+	if n < 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).MulRange(1, int64(n))
+}
+
+// StirlingSecond returns the Stirling number of the second kind S(n, k), the
+// number of partitions of a set with n elements into exactly k blocks. It is
+// 0 for negative arguments.
+func StirlingSecond(n, k int) *big.Int {
+	if n < 0 || k < 0 || k > n {
+		return new(big.Int)
+	}
+
+	// row holds S(m, 0), …, S(m, k) for increasing m, updated in place from
+	// the recurrence S(m, j) = j·S(m-1, j) + S(m-1, j-1).
+	row := make([]*big.Int, k+1)
+	for j := range row {
+		row[j] = new(big.Int)
+	}
+	row[0].SetInt64(1)
+	for m := 1; m <= n; m++ {
+		for j := min(m, k); j >= 1; j-- {
+			row[j].Mul(row[j], big.NewInt(int64(j)))
+			row[j].Add(row[j], row[j-1])
+		}
+		row[0].SetInt64(0)
+	}
+	return row[k]
+}
+
+// Bell returns the Bell number B(n), the number of partitions of a set with n
+// elements. It is 0 for negative n.
+func Bell(n int) *big.Int {
+	if n < 0 {
+		return new(big.Int)
+	}
+
+	// Build the Bell triangle: each row starts with the last entry of the
+	// previous row, and each further entry adds the entry above it. The first
+	// entry of row n is B(n).
+	row := []*big.Int{big.NewInt(1)}
+	for range n {
+		next := make([]*big.Int, len(row)+1)
+		next[0] = row[len(row)-1]
+		for j, above := range row {
+			next[j+1] = new(big.Int).Add(next[j], above)
+		}
+		row = next
+	}
+	return new(big.Int).Set(row[0])
+}
@@ -0,0 +1,155 @@
+package set
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func TestCountingFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      *big.Int
+		expected string
+	}{
+		{"Binomial(5, 2)", Binomial(5, 2), "10"},
+		{"Binomial(5, 0)", Binomial(5, 0), "1"},
+		{"Binomial(5, 6)", Binomial(5, 6), "0"},
+		{"Binomial(5, -1)", Binomial(5, -1), "0"},
+		{"Binomial(100, 50)", Binomial(100, 50), "100891344545564193334812497256"},
+		{"Factorial(0)", Factorial(0), "1"},
+		{"Factorial(25)", Factorial(25), "15511210043330985984000000"},
+		{"Factorial(-1)", Factorial(-1), "0"},
+		{"StirlingSecond(0, 0)", StirlingSecond(0, 0), "1"},
+		{"StirlingSecond(4, 0)", StirlingSecond(4, 0), "0"},
+		{"StirlingSecond(4, 2)", StirlingSecond(4, 2), "7"},
+		{"StirlingSecond(10, 3)", StirlingSecond(10, 3), "9330"},
+		{"StirlingSecond(3, 4)", StirlingSecond(3, 4), "0"},
+		{"Bell(0)", Bell(0), "1"},
+		{"Bell(5)", Bell(5), "52"},
+		{"Bell(10)", Bell(10), "115975"},
+		{"Bell(30)", Bell(30), "846749014511809332450147"},
+		{"Bell(-1)", Bell(-1), "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.String() != tt.expected {
+				t.Errorf("%s = %v, want %s", tt.name, tt.got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCountingIdentities(t *testing.T) {
+	for n := range 30 {
+		// n choose k = n! / (k! (n-k)!), and the row sums to 2^n.
+		rowSum := new(big.Int)
+		for k := 0; k <= n; k++ {
+			formula := new(big.Int).Div(Factorial(n), new(big.Int).Mul(Factorial(k), Factorial(n-k)))
+			if Binomial(n, k).Cmp(formula) != 0 {
+				t.Errorf("Binomial(%d, %d) = %v, want %v", n, k, Binomial(n, k), formula)
+			}
+			rowSum.Add(rowSum, Binomial(n, k))
+		}
+		if rowSum.Cmp(new(big.Int).Lsh(big.NewInt(1), uint(n))) != 0 {
+			t.Errorf("sum of Binomial(%d, k) = %v, want 2^%d", n, rowSum, n)
+		}
+
+		// B(n) is the sum of S(n, k) over all k.
+		stirlingSum := new(big.Int)
+		for k := 0; k <= n; k++ {
+			stirlingSum.Add(stirlingSum, StirlingSecond(n, k))
+		}
+		if Bell(n).Cmp(stirlingSum) != 0 {
+			t.Errorf("Bell(%d) = %v, want sum of StirlingSecond = %v", n, Bell(n), stirlingSum)
+		}
+	}
+}
+
+func TestCombinations(t *testing.T) {
+	s := newMockSet(1, 2, 3, 4, 5, 6)
+	for k := -1; k <= 7; k++ {
+		seen := NewHashSet[FrozenSet[int]]()
+		for c := range Combinations[int](s, k) {
+			if c.Cardinality() != k || !c.IsSubsetOf(s) {
+				t.Errorf("Combinations(%d) yielded %v", k, c)
+			}
+			seen.Insert(Freeze(c))
+		}
+		if int64(seen.Cardinality()) != Binomial(6, k).Int64() {
+			t.Errorf("Combinations(%d) yielded %d distinct subsets, want %v", k, seen.Cardinality(), Binomial(6, k))
+		}
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	for n := range 7 {
+		s := NewHashSet[int]()
+		for i := range n {
+			s.Insert(i)
+		}
+
+		seen := NewHashSet[string]()
+		for p := range Permutations(s) {
+			if len(p) != n || !Collect(slices.Values(p)).Equals(s) {
+				t.Errorf("Permutations() yielded %v, not an ordering of %v", p, s)
+			}
+			seen.Insert(fmt.Sprint(p))
+		}
+		if int64(seen.Cardinality()) != Factorial(n).Int64() {
+			t.Errorf("Permutations() of %d elements yielded %d distinct orderings, want %v", n, seen.Cardinality(), Factorial(n))
+		}
+	}
+
+	count := 0
+	for range Permutations[int](newMockSet(1, 2, 3, 4)) {
+		count++
+		if count == 5 {
+			break
+		}
+	}
+	if count != 5 {
+		t.Errorf("Permutations() yielded %d orderings after break at 5", count)
+	}
+}
+
+func TestPartitions(t *testing.T) {
+	for n := range 8 {
+		s := NewHashSet[int]()
+		for i := range n {
+			s.Insert(i)
+		}
+
+		seen := NewHashSet[FrozenSet[FrozenSet[int]]]()
+		blockCounts := make([]int64, n+1)
+		for partition := range Partitions(s) {
+			union := NewHashSet[int]()
+			blocks := NewHashSet[FrozenSet[int]]()
+			total := 0
+			for _, block := range partition {
+				if block.IsEmpty() {
+					t.Errorf("partition %v has an empty block", partition)
+				}
+				union = union.Union(block)
+				total += block.Cardinality()
+				blocks.Insert(Freeze(block))
+			}
+			if !union.Equals(s) || total != n {
+				t.Errorf("partition %v is not a partition of %v", partition, s)
+			}
+			seen.Insert(Freeze(blocks))
+			blockCounts[len(partition)]++
+		}
+
+		if int64(seen.Cardinality()) != Bell(n).Int64() {
+			t.Errorf("Partitions() of %d elements yielded %d distinct partitions, want %v", n, seen.Cardinality(), Bell(n))
+		}
+		for k, count := range blockCounts {
+			if count != StirlingSecond(n, k).Int64() {
+				t.Errorf("Partitions() of %d elements yielded %d with %d blocks, want %v", n, count, k, StirlingSecond(n, k))
+			}
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// concurrentSetIDs hands out the identifiers used to order lock acquisition
+// between two ConcurrentSets.
+var concurrentSetIDs atomic.Uint64
+
+// ConcurrentSet implements the Set interface with a hashSet guarded by a
+// read-write mutex, so it is safe for use by multiple goroutines.
+//
+// Reads take the read lock and may run in parallel; writes take the write lock.
+// Operations between two ConcurrentSets lock both sets for their whole duration,
+// in a fixed global order, so the result reflects a consistent snapshot of each
+// operand and concurrent calls cannot deadlock on lock ordering.
+//
+// A ConcurrentSet must be created with NewConcurrentSet and must not be copied
+// after first use.
+type ConcurrentSet[T comparable] struct {
+	mu       sync.RWMutex
+	id       uint64
+	elements hashSet[T]
+}
+
+// NewConcurrentSet creates and returns a new empty set that is safe for concurrent use.
+func NewConcurrentSet[T comparable]() *ConcurrentSet[T] {
+	return newConcurrentSet(make(map[T]struct{}))
+}
+
+func newConcurrentSet[T comparable](elements map[T]struct{}) *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{
+		id:       concurrentSetIDs.Add(1),
+		elements: hashSet[T]{elements: elements},
+	}
+}
+
+func (c *ConcurrentSet[T]) emptyLike() Set[T] {
+	return NewConcurrentSet[T]()
+}
+
+// wrapConcurrent takes ownership of a hashSet produced by an operation and
+// returns it as a ConcurrentSet.
+func wrapConcurrent[T comparable](s Set[T]) *ConcurrentSet[T] {
+	return newConcurrentSet(s.(*hashSet[T]).elements)
+}
+
+// withRead calls fn with the read lock held on c and, when other is also a
+// ConcurrentSet, on other. The Set passed to fn is safe to read without
+// further locking for the duration of the call.
+func (c *ConcurrentSet[T]) withRead(other Set[T], fn func(o Set[T])) {
+	o, ok := other.(*ConcurrentSet[T])
+	if !ok {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		fn(other)
+		return
+	}
+
+	if o == c {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		fn(&c.elements)
+		return
+	}
+
+	// Always acquire the lock of the older set first. Every operation between
+	// two ConcurrentSets follows this order, which rules out lock cycles.
+	first, second := c, o
+	if second.id < first.id {
+		first, second = second, first
+	}
+	first.mu.RLock()
+	defer first.mu.RUnlock()
+	second.mu.RLock()
+	defer second.mu.RUnlock()
+	fn(&o.elements)
+}
+
+// withWrite calls fn with the write lock held on c and, when other is a
+// different ConcurrentSet, the read lock held on other. Locks are acquired in
+// the same order as in withRead.
+func (c *ConcurrentSet[T]) withWrite(other Set[T], fn func(o Set[T])) {
+	o, ok := other.(*ConcurrentSet[T])
+	if !ok {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		fn(other)
+		return
+	}
+
+	if o == c {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		fn(&c.elements)
+		return
+	}
+
+	if c.id < o.id {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		o.mu.RLock()
+		defer o.mu.RUnlock()
+	} else {
+		o.mu.RLock()
+		defer o.mu.RUnlock()
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	}
+	fn(&o.elements)
+}
+
+func (c *ConcurrentSet[T]) Insert(elem T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.elements.Insert(elem)
+}
+
+func (c *ConcurrentSet[T]) Remove(elem T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.elements.Remove(elem)
+}
+
+// InsertIfAbsent adds the element to the set and reports whether it was added.
+// It returns false if the element was already present. The check and the
+// insertion happen atomically.
+func (c *ConcurrentSet[T]) InsertIfAbsent(elem T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.elements.Contains(elem) {
+		return false
+	}
+	c.elements.Insert(elem)
+	return true
+}
+
+// RemoveAndReport deletes the element from the set and reports whether it was
+// present. The check and the removal happen atomically.
+func (c *ConcurrentSet[T]) RemoveAndReport(elem T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.elements.Contains(elem) {
+		return false
+	}
+	c.elements.Remove(elem)
+	return true
+}
+
+func (c *ConcurrentSet[T]) InsertAll(elems ...T) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.elements.InsertAll(elems...)
+}
+
+func (c *ConcurrentSet[T]) RemoveAll(elems ...T) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.elements.RemoveAll(elems...)
+}
+
+// RetainIf deletes every element for which keep returns false and returns the
+// number of elements deleted. The whole operation is atomic: keep is called
+// with the write lock held, so it must not use the set.
+func (c *ConcurrentSet[T]) RetainIf(keep func(T) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.elements.RetainIf(keep)
+}
+
+// RemoveIf deletes every element for which remove returns true and returns the
+// number of elements deleted. The whole operation is atomic: remove is called
+// with the write lock held, so it must not use the set.
+func (c *ConcurrentSet[T]) RemoveIf(remove func(T) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.elements.RemoveIf(remove)
+}
+
+func (c *ConcurrentSet[T]) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.elements.Clear()
+}
+
+func (c *ConcurrentSet[T]) Pop() (T, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.elements.Pop()
+}
+
+func (c *ConcurrentSet[T]) Contains(elem T) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.elements.Contains(elem)
+}
+
+func (c *ConcurrentSet[T]) Cardinality() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.elements.Cardinality()
+}
+
+func (c *ConcurrentSet[T]) IsEmpty() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.elements.IsEmpty()
+}
+
+// All returns an iterator over a snapshot of the elements of the set.
+// The lock is not held while elements are yielded, so the loop body may
+// freely modify the set; such modifications are not seen by the iteration.
+func (c *ConcurrentSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, elem := range c.ToSlice() {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+func (c *ConcurrentSet[T]) ToSlice() []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.elements.ToSlice()
+}
+
+func (c *ConcurrentSet[T]) String() string {
+	return formatLiteral(sortForDisplay(c.ToSlice()), formatElement)
+}
+
+// Format implements fmt.Formatter, formatting a snapshot of the set.
+func (c *ConcurrentSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, sortForDisplay(c.ToSlice()), "set.NewConcurrentSet["+typeArg[T]()+"]()", "*set.ConcurrentSet["+typeArg[T]()+"]")
+}
+
+func (c *ConcurrentSet[T]) Equals(other Set[T]) bool {
+	var result bool
+	c.withRead(other, func(o Set[T]) { result = c.elements.Equals(o) })
+	return result
+}
+
+func (c *ConcurrentSet[T]) IsSubsetOf(other Set[T]) bool {
+	var result bool
+	c.withRead(other, func(o Set[T]) { result = c.elements.IsSubsetOf(o) })
+	return result
+}
+
+func (c *ConcurrentSet[T]) IsSupersetOf(other Set[T]) bool {
+	var result bool
+	c.withRead(other, func(o Set[T]) { result = c.elements.IsSupersetOf(o) })
+	return result
+}
+
+func (c *ConcurrentSet[T]) IsProperSubsetOf(other Set[T]) bool {
+	var result bool
+	c.withRead(other, func(o Set[T]) { result = c.elements.IsProperSubsetOf(o) })
+	return result
+}
+
+func (c *ConcurrentSet[T]) IsProperSupersetOf(other Set[T]) bool {
+	var result bool
+	c.withRead(other, func(o Set[T]) { result = c.elements.IsProperSupersetOf(o) })
+	return result
+}
+
+// Union returns a new ConcurrentSet containing all elements that are in either
+// this set or the other set (**X** ∪ **Y**).
+func (c *ConcurrentSet[T]) Union(other Set[T]) Set[T] {
+	var result Set[T]
+	c.withRead(other, func(o Set[T]) { result = c.elements.Union(o) })
+	return wrapConcurrent(result)
+}
+
+// Intersection returns a new ConcurrentSet containing all elements that are in
+// both this set and the other set (**X** ∩ **Y**).
+func (c *ConcurrentSet[T]) Intersection(other Set[T]) Set[T] {
+	var result Set[T]
+	c.withRead(other, func(o Set[T]) { result = c.elements.Intersection(o) })
+	return wrapConcurrent(result)
+}
+
+// Difference returns a new ConcurrentSet containing all elements that are in
+// this set but not in the other set (**X** \ **Y**).
+func (c *ConcurrentSet[T]) Difference(other Set[T]) Set[T] {
+	var result Set[T]
+	c.withRead(other, func(o Set[T]) { result = c.elements.Difference(o) })
+	return wrapConcurrent(result)
+}
+
+// SymmetricDifference returns a new ConcurrentSet containing all elements that
+// are in either this set or the other set, but not in both (**X** Δ **Y**).
+func (c *ConcurrentSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	var result Set[T]
+	c.withRead(other, func(o Set[T]) { result = c.elements.SymmetricDifference(o) })
+	return wrapConcurrent(result)
+}
+
+func (c *ConcurrentSet[T]) UnionWith(other Set[T]) int {
+	var result int
+	c.withWrite(other, func(o Set[T]) { result = c.elements.UnionWith(o) })
+	return result
+}
+
+func (c *ConcurrentSet[T]) IntersectWith(other Set[T]) int {
+	var result int
+	c.withWrite(other, func(o Set[T]) { result = c.elements.IntersectWith(o) })
+	return result
+}
+
+func (c *ConcurrentSet[T]) DifferenceWith(other Set[T]) int {
+	var result int
+	c.withWrite(other, func(o Set[T]) { result = c.elements.DifferenceWith(o) })
+	return result
+}
+
+func (c *ConcurrentSet[T]) SymmetricDifferenceWith(other Set[T]) int {
+	var result int
+	c.withWrite(other, func(o Set[T]) { result = c.elements.SymmetricDifferenceWith(o) })
+	return result
+}
@@ -0,0 +1,225 @@
+package set
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrentSetOperations(t *testing.T) {
+	a := NewConcurrentSet[int]()
+	b := NewConcurrentSet[int]()
+	for _, v := range []int{1, 2, 3, 4} {
+		a.Insert(v)
+	}
+	for _, v := range []int{3, 4, 5} {
+		b.Insert(v)
+	}
+
+	tests := []struct {
+		name     string
+		result   Set[int]
+		expected []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersection", a.Intersection(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"Union with hashSet", a.Union(Collect(newMockSet(9).All())), []int{1, 2, 3, 4, 9}},
+		{"Intersection with itself", a.Intersection(a), []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.result.(*ConcurrentSet[int]); !ok {
+				t.Errorf("%s returned %T, want *ConcurrentSet[int]", tt.name, tt.result)
+			}
+			if !tt.result.Equals(newMockSet(tt.expected...)) {
+				t.Errorf("%s = %v, want %v", tt.name, tt.result, tt.expected)
+			}
+		})
+	}
+
+	if !a.IsProperSupersetOf(a.Intersection(b)) || a.IsProperSubsetOf(a) || !a.IsSubsetOf(a) {
+		t.Error("subset relations between ConcurrentSets are incorrect")
+	}
+	if !a.Intersection(b).IsSubsetOf(b) || !b.IsSupersetOf(newMockSet(5)) {
+		t.Error("subset relations with other implementations are incorrect")
+	}
+}
+
+func TestConcurrentSetAtomicOperations(t *testing.T) {
+	s := NewConcurrentSet[string]()
+
+	if !s.InsertIfAbsent("a") {
+		t.Error("InsertIfAbsent() on a new element = false, want true")
+	}
+	if s.InsertIfAbsent("a") {
+		t.Error("InsertIfAbsent() on an existing element = true, want false")
+	}
+	if !s.RemoveAndReport("a") {
+		t.Error("RemoveAndReport() on an existing element = false, want true")
+	}
+	if s.RemoveAndReport("a") {
+		t.Error("RemoveAndReport() on a missing element = true, want false")
+	}
+	if !s.IsEmpty() {
+		t.Errorf("set should be empty, got %v", s)
+	}
+}
+
+func TestConcurrentSetAllAllowsModification(t *testing.T) {
+	s := NewConcurrentSet[int]()
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+
+	// Modifying the set while ranging over it must not deadlock.
+	count := 0
+	for elem := range s.All() {
+		s.Remove(elem)
+		s.Insert(elem + 100)
+		count++
+	}
+	if count != 10 {
+		t.Errorf("All() yielded %d elements, want 10", count)
+	}
+	if s.Cardinality() != 10 || s.Contains(0) || !s.Contains(100) {
+		t.Errorf("unexpected set after modification: %v", s)
+	}
+}
+
+func TestConcurrentSetRace(t *testing.T) {
+	const workers = 8
+	const perWorker = 500
+
+	s := NewConcurrentSet[int]()
+	var added atomic.Int64
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every worker tries to insert the same elements, so each element
+			// must be reported as added exactly once overall.
+			for i := 0; i < perWorker; i++ {
+				if s.InsertIfAbsent(i) {
+					added.Add(1)
+				}
+				_ = s.Contains(i)
+				_ = s.Cardinality()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if added.Load() != perWorker {
+		t.Errorf("InsertIfAbsent reported %d additions, want %d", added.Load(), perWorker)
+	}
+	if s.Cardinality() != perWorker {
+		t.Errorf("Cardinality() = %d, want %d", s.Cardinality(), perWorker)
+	}
+
+	var removed atomic.Int64
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				if s.RemoveAndReport(i) {
+					removed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if removed.Load() != perWorker {
+		t.Errorf("RemoveAndReport reported %d removals, want %d", removed.Load(), perWorker)
+	}
+}
+
+func TestConcurrentSetNoDeadlock(t *testing.T) {
+	a := NewConcurrentSet[int]()
+	b := NewConcurrentSet[int]()
+	for i := 0; i < 100; i++ {
+		a.Insert(i)
+		b.Insert(i + 50)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(4)
+		// Operations in both directions combined with writers would deadlock
+		// if locks were not acquired in a consistent order.
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				_ = a.Union(b)
+				_ = a.Equals(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				_ = b.Intersection(a)
+				_ = b.IsSubsetOf(a)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				a.Insert(1000 + i)
+				b.Remove(50 + i%50)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				a.UnionWith(b)
+				b.DifferenceWith(a)
+				b.InsertAll(50+i%50, 1050+i%50)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentSetBulkRace(t *testing.T) {
+	const n = 500
+
+	elems := make([]int, n)
+	for i := range elems {
+		elems[i] = i
+	}
+
+	// Each bulk operation is atomic, so every element is counted by exactly
+	// one worker.
+	s := NewConcurrentSet[int]()
+	var added, removed atomic.Int64
+	runWorkers(8, func() { added.Add(int64(s.InsertAll(elems...))) })
+	runWorkers(8, func() {
+		removed.Add(int64(s.RemoveIf(func(elem int) bool { return elem%2 == 0 })))
+	})
+
+	if added.Load() != n || removed.Load() != n/2 {
+		t.Errorf("InsertAll added %d and RemoveIf removed %d, want %d and %d", added.Load(), removed.Load(), n, n/2)
+	}
+	if s.Cardinality() != n/2 {
+		t.Errorf("Cardinality() = %d, want %d", s.Cardinality(), n/2)
+	}
+}
+
+// runWorkers runs fn in the given number of goroutines and waits for them.
+func runWorkers(workers int, fn func()) {
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn()
+		}()
+	}
+	wg.Wait()
+}
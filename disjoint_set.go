@@ -0,0 +1,161 @@
+package set
+
+// DisjointSet, also known as union-find, partitions its elements into
+// disjoint equivalence classes. Union merges the classes of two elements and
+// Find returns the representative of an element's class, in nearly constant
+// amortized time thanks to union by rank and path compression. Computing
+// classes this way is much faster than merging sets with repeated unions.
+//
+// DisjointSet is not safe for concurrent use. The zero value of a DisjointSet
+// is empty and ready to use.
+type DisjointSet[T comparable] struct {
+	// index maps every element to its position in elems, parent and rank.
+	index  map[T]int
+	elems  []T
+	parent []int
+	rank   []uint8
+
+	components int
+}
+
+// NewDisjointSet creates and returns a new empty disjoint set.
+func NewDisjointSet[T comparable]() *DisjointSet[T] {
+	return &DisjointSet[T]{index: make(map[T]int)}
+}
+
+// Add adds elem in a class of its own and reports whether it was added. It
+// has no effect if elem is already present.
+func (d *DisjointSet[T]) Add(elem T) bool {
+	if _, exists := d.index[elem]; exists {
+		return false
+	}
+	d.add(elem)
+	return true
+}
+
+// add adds elem, which must not be present, and returns its index.
+func (d *DisjointSet[T]) add(elem T) int {
+	if d.index == nil {
+		d.index = make(map[T]int)
+	}
+	i := len(d.elems)
+	d.index[elem] = i
+	d.elems = append(d.elems, elem)
+	d.parent = append(d.parent, i)
+	d.rank = append(d.rank, 0)
+	d.components++
+	return i
+}
+
+// indexOf returns the index of elem, adding it if it is not present.
+func (d *DisjointSet[T]) indexOf(elem T) int {
+	if i, exists := d.index[elem]; exists {
+		return i
+	}
+	return d.add(elem)
+}
+
+// root returns the index of the representative of the element at index i,
+// pointing every element on the way directly at it.
+func (d *DisjointSet[T]) root(i int) int {
+	r := i
+	for d.parent[r] != r {
+		r = d.parent[r]
+	}
+	for d.parent[i] != r {
+		d.parent[i], i = r, d.parent[i]
+	}
+	return r
+}
+
+// Find returns the representative of the class of elem. Two elements are in
+// the same class if and only if they have the same representative, which may
+// change after a Union. The boolean is false if elem is not present.
+func (d *DisjointSet[T]) Find(elem T) (T, bool) {
+	i, exists := d.index[elem]
+	if !exists {
+		var zero T
+		return zero, false
+	}
+	return d.elems[d.root(i)], true
+}
+
+// Union merges the classes of a and b, adding either of them first if it is
+// not present. It reports whether they were in different classes.
+func (d *DisjointSet[T]) Union(a, b T) bool {
+	ra, rb := d.root(d.indexOf(a)), d.root(d.indexOf(b))
+	if ra == rb {
+		return false
+	}
+	// Attach the shallower tree under the deeper one to keep trees flat.
+	switch {
+	case d.rank[ra] < d.rank[rb]:
+		d.parent[ra] = rb
+	case d.rank[ra] > d.rank[rb]:
+		d.parent[rb] = ra
+	default:
+		d.parent[rb] = ra
+		d.rank[ra]++
+	}
+	d.components--
+	return true
+}
+
+// Connected reports whether a and b are in the same class. An element that is
+// not present is connected to nothing.
+func (d *DisjointSet[T]) Connected(a, b T) bool {
+	i, okA := d.index[a]
+	j, okB := d.index[b]
+	return okA && okB && d.root(i) == d.root(j)
+}
+
+// Contains reports whether elem is present.
+func (d *DisjointSet[T]) Contains(elem T) bool {
+	_, exists := d.index[elem]
+	return exists
+}
+
+// Len returns the number of elements.
+func (d *DisjointSet[T]) Len() int {
+	return len(d.elems)
+}
+
+// Components returns the number of classes.
+func (d *DisjointSet[T]) Components() int {
+	return d.components
+}
+
+// Class returns a new set of the elements in the same class as elem, or an
+// empty set if elem is not present.
+func (d *DisjointSet[T]) Class(elem T) Set[T] {
+	result := NewHashSet[T]()
+	i, exists := d.index[elem]
+	if !exists {
+		return result
+	}
+	r := d.root(i)
+	for j, e := range d.elems {
+		if d.root(j) == r {
+			result.Insert(e)
+		}
+	}
+	return result
+}
+
+// Classes returns every class as a new set. The classes are ordered by the
+// first element of each that was added.
+func (d *DisjointSet[T]) Classes() []Set[T] {
+	classes := make([]Set[T], 0, d.components)
+	byRoot := make(map[int]Set[T], d.components)
+	for i, elem := range d.elems {
+		r := d.root(i)
+		class, exists := byRoot[r]
+		if !exists {
+			class = NewHashSet[T]()
+			byRoot[r] = class
+			classes = append(classes, class)
+		}
+		class.Insert(elem)
+	}
+	return classes
+}
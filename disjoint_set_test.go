@@ -0,0 +1,73 @@
+package set
+
+import "testing"
+
+func TestDisjointSet(t *testing.T) {
+	var d DisjointSet[string]
+	if _, ok := d.Find("a"); ok || d.Connected("a", "a") || d.Components() != 0 {
+		t.Fatal("zero DisjointSet is not empty")
+	}
+
+	if !d.Add("a") || d.Add("a") || !d.Connected("a", "a") {
+		t.Error("Add() did not add a single element once")
+	}
+	if !d.Union("a", "b") || !d.Union("c", "d") || d.Union("b", "a") {
+		t.Error("Union() did not report merges correctly")
+	}
+	d.Add("e")
+	if d.Len() != 5 || d.Components() != 3 {
+		t.Errorf("Len(), Components() = %d, %d, want 5, 3", d.Len(), d.Components())
+	}
+	if d.Connected("a", "c") || !d.Connected("c", "d") || d.Connected("a", "z") {
+		t.Error("Connected() before merging the classes is wrong")
+	}
+
+	d.Union("b", "d")
+	if !d.Connected("a", "c") || d.Components() != 2 {
+		t.Error("Union() did not merge the classes of a and c")
+	}
+	ra, _ := d.Find("a")
+	rd, _ := d.Find("d")
+	if ra != rd {
+		t.Errorf("Find(a) = %q, Find(d) = %q, want the same representative", ra, rd)
+	}
+
+	classes := d.Classes()
+	if len(classes) != 2 {
+		t.Fatalf("Classes() = %v, want 2 classes", classes)
+	}
+	if !classes[0].Equals(newMockSet("a", "b", "c", "d")) || !classes[1].Equals(newMockSet("e")) {
+		t.Errorf("Classes() = %v", classes)
+	}
+	if !d.Class("c").Equals(classes[0]) || !d.Class("z").IsEmpty() {
+		t.Errorf("Class(c) = %v, want %v", d.Class("c"), classes[0])
+	}
+}
+
+func TestDisjointSetLarge(t *testing.T) {
+	const n = 100000
+	d := NewDisjointSet[int]()
+	for i := range n {
+		d.Union(i, i%10)
+	}
+	if d.Components() != 10 || d.Len() != n {
+		t.Fatalf("Components(), Len() = %d, %d, want 10, %d", d.Components(), d.Len(), n)
+	}
+	for i := range n {
+		if !d.Connected(i, i%10) {
+			t.Fatalf("%d is not connected to %d", i, i%10)
+		}
+	}
+
+	// The trees stay flat under union by rank.
+	for i := range d.rank {
+		if d.rank[i] > 17 {
+			t.Fatalf("rank %d exceeds log2(%d)", d.rank[i], n)
+		}
+	}
+	for _, class := range d.Classes() {
+		if class.Cardinality() != n/10 {
+			t.Errorf("class of %d elements, want %d", class.Cardinality(), n/10)
+		}
+	}
+}
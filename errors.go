@@ -0,0 +1,35 @@
+package set
+
+import "errors"
+
+// The sentinel errors below are returned wrapped with details about the
+// failing operation. Test for them with errors.Is.
+var (
+	// ErrTooLarge is returned when the result of an operation would be too
+	// large to build in memory.
+	ErrTooLarge = errors.New("set: result too large")
+
+	// ErrIncompatibleImplementation is returned when an operation between two
+	// sets fails because one implementation cannot work with the other, for
+	// example because it type-asserts its argument to its own type.
+	ErrIncompatibleImplementation = errors.New("set: incompatible implementation")
+
+	// ErrNilSet is returned when a set passed to an operation is nil.
+	ErrNilSet = errors.New("set: nil set")
+
+	// ErrInvalidEncoding is returned when decoding binary data that is not a
+	// valid encoding of a set of the expected element type.
+	ErrInvalidEncoding = errors.New("set: invalid binary encoding")
+
+	// ErrInvalidRoaringFormat is returned when decoding data that is not a
+	// valid serialized Roaring bitmap.
+	ErrInvalidRoaringFormat = errors.New("set: invalid roaring bitmap format")
+
+	// ErrInvalidSQLValue is returned when a database value cannot be scanned
+	// into a set, or a set cannot be stored in the requested format.
+	ErrInvalidSQLValue = errors.New("set: invalid SQL value")
+
+	// ErrDuplicateElement is returned when decoding data that holds the same
+	// element more than once, if duplicates are rejected.
+	ErrDuplicateElement = errors.New("set: duplicate element")
+)
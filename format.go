@@ -0,0 +1,142 @@
+package set
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Sets print their elements in a deterministic order. Sets without an order of
+// their own, such as hashSet, ConcurrentSet, PersistentSet and FrozenSet, sort
+// them: elements whose underlying type is a string, integer, floating-point or
+// boolean type by value, Pair, Pair2 and Triple elements lexicographically by
+// their components, and other elements by their text. SortedSet, BitSet and
+// RoaringBitmap print in ascending order and LinkedHashSet in insertion order.
+//
+// All sets implement fmt.Formatter with these verbs:
+//
+//	%v, %s  the set as returned by String, such as {1, 2, 3}
+//	%+v     the set followed by its cardinality, such as {1, 2, 3} (cardinality 3)
+//	%#v     a Go expression constructing the set
+//
+// Any other verb, such as %q or %x, formats each element with that verb and
+// its flags, as for slices: %q of a set of strings prints {"a", "b"}. Width
+// and precision given with %v and %s apply to the whole set.
+
+// tuple is implemented by the tuple types, whose elements are ordered
+// lexicographically by their components for display.
+type tuple interface {
+	components() []any
+}
+
+func (p Pair[T]) components() []any {
+	return []any{p.First, p.Second}
+}
+
+func (p Pair2[A, B]) components() []any {
+	return []any{p.First, p.Second}
+}
+
+func (t Triple[A, B, C]) components() []any {
+	return []any{t.First, t.Second, t.Third}
+}
+
+// sortForDisplay sorts elems in place in the order in which sets without an
+// order of their own print their elements, and returns it.
+func sortForDisplay[T comparable](elems []T) []T {
+	if compare := orderedCompare[T](); compare != nil {
+		slices.SortFunc(elems, compare)
+	} else {
+		slices.SortFunc(elems, func(a, b T) int { return compareDisplay(a, b) })
+	}
+	return elems
+}
+
+// compareDisplay compares two elements for display. Numbers, strings and
+// booleans of the same type compare by value and tuples component by
+// component. Anything else compares by its text, then by the name of its
+// type, so that equal texts of different types still sort deterministically.
+func compareDisplay(a, b any) int {
+	if ta, ok := a.(tuple); ok {
+		if tb, ok := b.(tuple); ok {
+			return slices.CompareFunc(ta.components(), tb.components(), compareDisplay)
+		}
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.IsValid() && vb.IsValid() && va.Type() == vb.Type() && isScalarKind(va.Kind()) {
+		return compareScalars(va, vb)
+	}
+	if c := strings.Compare(formatElement(a), formatElement(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(fmt.Sprintf("%T", a), fmt.Sprintf("%T", b))
+}
+
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// formatLiteral returns the set literal of elems, formatting each element
+// with format.
+func formatLiteral[T comparable](elems []T, format func(any) string) string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, elem := range elems {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(format(elem))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// formatSet implements fmt.Formatter for a set with the elements elems, in
+// display order. For %#v, newSet is the Go expression creating an empty set of
+// the same kind and setType the type of that expression.
+func formatSet[T comparable](f fmt.State, verb rune, elems []T, newSet, setType string) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, goSyntax(elems, newSet, setType))
+	case verb == 'v' || verb == 's':
+		text := formatLiteral(elems, formatElement)
+		if verb == 'v' && f.Flag('+') {
+			text += fmt.Sprintf(" (cardinality %d)", len(elems))
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, 's'), text)
+	default:
+		format := fmt.FormatString(f, verb)
+		fmt.Fprint(f, formatLiteral(elems, func(elem any) string { return fmt.Sprintf(format, elem) }))
+	}
+}
+
+// goSyntax returns a Go expression that evaluates to a set of setType with
+// the elements elems, starting from the empty set newSet.
+func goSyntax[T comparable](elems []T, newSet, setType string) string {
+	if len(elems) == 0 {
+		return newSet
+	}
+	return "func() " + setType + " { s := " + newSet + "; s.InsertAll(" + goElements(elems) + "); return s }()"
+}
+
+// goElements returns elems in Go syntax, separated by commas.
+func goElements[T comparable](elems []T) string {
+	texts := make([]string, len(elems))
+	for i, elem := range elems {
+		texts[i] = fmt.Sprintf("%#v", elem)
+	}
+	return strings.Join(texts, ", ")
+}
+
+// typeArg returns the name of T as written in a type argument.
+func typeArg[T any]() string {
+	return reflect.TypeFor[T]().String()
+}
@@ -0,0 +1,130 @@
+package set
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestStringOrder(t *testing.T) {
+	type point struct{ X, Y int }
+
+	tests := []struct {
+		name string
+		got  fmt.Stringer
+		want string
+	}{
+		{"ints", hashSetOf(10, -3, 9, 100), "{-3, 9, 10, 100}"},
+		{"floats", hashSetOf(2.5, -1.0, 0.25), "{-1, 0.25, 2.5}"},
+		{"unsigned", hashSetOf[uint8](200, 7), "{7, 200}"},
+		{"bools", hashSetOf(true, false), "{false, true}"},
+		{"pairs", hashSetOf(Pair[int]{2, 1}, Pair[int]{1, 10}, Pair[int]{1, 9}), "{(1, 9), (1, 10), (2, 1)}"},
+		{"mixed pairs", hashSetOf(Pair2[string, int]{"b", 1}, Pair2[string, int]{"a", 10}, Pair2[string, int]{"a", 9}), "{(a, 9), (a, 10), (b, 1)}"},
+		{"triples", hashSetOf(Triple[int, int, int]{1, 2, 10}, Triple[int, int, int]{1, 2, 3}), "{(1, 2, 3), (1, 2, 10)}"},
+		{"structs by text", hashSetOf(point{2, 1}, point{1, 2}, point{1, 10}), "{{1 10}, {1 2}, {2 1}}"},
+		{"mixed", hashSetOf[any](2, "b", 10, true, "a"), "{2, 10, a, b, true}"},
+		{"frozen sets", hashSetOf(NewFrozenSet(3, 1), NewFrozenSet(2), NewFrozenSet[int]()), "{{1, 3}, {2}, {}}"},
+		{"frozen set", NewFrozenSet(30, 4, 200), "{4, 30, 200}"},
+		{"persistent", NewPersistentSet[int]().With(11).With(2), "{2, 11}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 5 {
+				if got := tt.got.String(); got != tt.want {
+					t.Fatalf("String() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func hashSetOf[T comparable](elems ...T) Set[T] {
+	s := NewHashSet[T]()
+	s.InsertAll(elems...)
+	return s
+}
+
+func TestFormat(t *testing.T) {
+	words := hashSetOf("b", "a c")
+	linked := newLinkedHashSet(3, 1, 2)
+	bits := NewBitSet()
+	bits.InsertAll(5, 1)
+
+	tests := []struct {
+		format string
+		arg    any
+		want   string
+	}{
+		{"%v", words, "{a c, b}"},
+		{"%s", words, "{a c, b}"},
+		{"%+v", words, "{a c, b} (cardinality 2)"},
+		{"%q", words, `{"a c", "b"}`},
+		{"%12v|", words, "    {a c, b}|"},
+		{"%-12v|", words, "{a c, b}    |"},
+		{"%+v", NewHashSet[int](), "{} (cardinality 0)"},
+		{"%v", linked, "{3, 1, 2}"},
+		{"%03d", linked, "{003, 001, 002}"},
+		{"%x", bits, "{1, 5}"},
+		{"%q", hashSetOf(Pair2[string, int]{"a", 1}), `{"(a, 1)"}`},
+		{"%v", []Set[int]{hashSetOf(2, 1)}, "[{1, 2}]"},
+		{"%#v", words, `func() set.Set[string] { s := set.NewHashSet[string](); s.InsertAll("a c", "b"); return s }()`},
+		{"%#v", NewHashSet[int](), "set.NewHashSet[int]()"},
+		{"%#v", linked, "func() *set.LinkedHashSet[int] { s := set.NewLinkedHashSet[int](); s.InsertAll(3, 1, 2); return s }()"},
+		{"%#v", bits, "func() *set.BitSet { s := set.NewBitSet(); s.InsertAll(0x1, 0x5); return s }()"},
+		{"%#v", NewShardedSet[int](3), "set.NewShardedSet[int](4)"},
+		{"%#v", NewPersistentSet[string]().With("y").With("x"), `set.NewPersistentSet[string]().With("x").With("y")`},
+		{"%#v", NewFrozenSet(2, 1), "set.NewFrozenSet[int](1, 2)"},
+		{"%#v", hashSetOf(NewFrozenSet[int]()), "func() set.Set[set.FrozenSet[int]] { s := set.NewHashSet[set.FrozenSet[int]](); s.InsertAll(set.NewFrozenSet[int]()); return s }()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.arg); got != tt.want {
+				t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAllImplementations(t *testing.T) {
+	sorted := NewSortedSet[int]()
+	concurrent := NewConcurrentSet[int]()
+	roaring := NewRoaringBitmap()
+	sharded := NewShardedSet[int](2)
+	sets := map[string]fmt.Formatter{
+		"hashSet":    hashSetOf(2, 1).(fmt.Formatter),
+		"sorted":     sorted,
+		"concurrent": concurrent,
+		"linked":     newLinkedHashSet(1, 2),
+		"persistent": NewPersistentSet[int]().With(2).With(1),
+		"roaring":    roaring,
+		"frozen":     NewFrozenSet(1, 2),
+		"sharded":    sharded.(fmt.Formatter),
+	}
+	sorted.InsertAll(2, 1)
+	concurrent.InsertAll(2, 1)
+	roaring.InsertAll(2, 1)
+	sharded.InsertAll(2, 1)
+
+	for name, s := range sets {
+		if got := fmt.Sprintf("%+v", s); got != "{1, 2} (cardinality 2)" {
+			t.Errorf("%s: Sprintf(%%+v) = %q", name, got)
+		}
+	}
+}
+
+func TestSortForDisplayAllocations(t *testing.T) {
+	ints := []int{5000, 3000, 9000, 1000} // boxing small integers would not allocate
+	strs := []string{"b", "c", "a"}
+	floats := []float64{2.5, -1, 0}
+	allocs := testing.AllocsPerRun(100, func() {
+		sortForDisplay(ints)
+		sortForDisplay(strs)
+		sortForDisplay(floats)
+		slices.SortFunc(ints, valueCompare[int]())
+	})
+	if allocs != 0 {
+		t.Errorf("sorting predeclared types allocates %v times, want 0", allocs)
+	}
+}
@@ -0,0 +1,228 @@
+package set
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"reflect"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// FrozenSet is an immutable set that can itself be the element of another
+// set. Two frozen sets with the same elements are always equal under ==, so
+// sets of sets such as Set[FrozenSet[T]] compare, deduplicate and look up
+// their elements by content:
+//
+//	family := NewHashSet[FrozenSet[int]]()
+//	family.Insert(NewFrozenSet(1, 2))
+//	family.Insert(NewFrozenSet(2, 1)) // no effect, {1, 2} is already present
+//	family.Contains(NewFrozenSet(1, 2)) // true
+//
+// This works by interning: every FrozenSet refers to the single canonical copy
+// of its contents, which is kept only as long as some FrozenSet still uses it.
+// Freezing a set therefore costs O(n) to hash and compare its elements.
+//
+// FrozenSet does not implement the Set interface, as it cannot be modified.
+// Use Thaw to obtain a mutable copy.
+//
+// The zero value of a FrozenSet is the empty set.
+type FrozenSet[T comparable] struct {
+	data *frozenData[T]
+}
+
+// frozenData is the canonical, never modified, contents of a FrozenSet.
+type frozenData[T comparable] struct {
+	elements hashSet[T]
+}
+
+// NewFrozenSet returns the frozen set of the given elements.
+func NewFrozenSet[T comparable](elems ...T) FrozenSet[T] {
+	s := &hashSet[T]{elements: make(map[T]struct{}, len(elems))}
+	for _, elem := range elems {
+		s.Insert(elem)
+	}
+	return freezeOwned(s)
+}
+
+// Freeze returns a frozen set with the same elements as s. Later changes to s
+// do not affect the result.
+func Freeze[T comparable](s Set[T]) FrozenSet[T] {
+	return freezeOwned(&hashSet[T]{elements: toMap(s)})
+}
+
+// toMap returns the elements of s as the keys of a new map.
+func toMap[T comparable](s Set[T]) map[T]struct{} {
+	result := make(map[T]struct{}, s.Cardinality())
+	for elem := range s.All() {
+		result[elem] = struct{}{}
+	}
+	return result
+}
+
+// freezeOwned freezes s, which must not be used by the caller afterwards.
+func freezeOwned[T comparable](s *hashSet[T]) FrozenSet[T] {
+	if s.IsEmpty() {
+		return FrozenSet[T]{}
+	}
+	return FrozenSet[T]{data: internTableFor[T]().intern(s)}
+}
+
+// Thaw returns a new mutable set with the elements of the frozen set.
+func (f FrozenSet[T]) Thaw() Set[T] {
+	return Collect(f.All())
+}
+
+// view returns the elements of the frozen set. It must not be modified.
+func (f FrozenSet[T]) view() *hashSet[T] {
+	if f.data == nil {
+		return &hashSet[T]{}
+	}
+	return &f.data.elements
+}
+
+// Contains reports whether elem is in the frozen set.
+func (f FrozenSet[T]) Contains(elem T) bool {
+	return f.view().Contains(elem)
+}
+
+// Cardinality returns the number of elements in the frozen set.
+func (f FrozenSet[T]) Cardinality() int {
+	return f.view().Cardinality()
+}
+
+// IsEmpty reports whether the frozen set has no elements.
+func (f FrozenSet[T]) IsEmpty() bool {
+	return f.data == nil
+}
+
+// All returns an iterator over the elements of the frozen set.
+func (f FrozenSet[T]) All() iter.Seq[T] {
+	return f.view().All()
+}
+
+// ToSlice returns a slice containing all elements of the frozen set.
+func (f FrozenSet[T]) ToSlice() []T {
+	return f.view().ToSlice()
+}
+
+// String returns a string representation of the frozen set, formatted like
+// that of a set returned by NewHashSet.
+func (f FrozenSet[T]) String() string {
+	return f.view().String()
+}
+
+// Format implements fmt.Formatter. With %#v, the expression is a call to
+// NewFrozenSet.
+func (f FrozenSet[T]) Format(s fmt.State, verb rune) {
+	elems := sortForDisplay(f.ToSlice())
+	if verb == 'v' && s.Flag('#') {
+		fmt.Fprintf(s, "set.NewFrozenSet[%s](%s)", typeArg[T](), goElements(elems))
+		return
+	}
+	formatSet(s, verb, elems, "", "")
+}
+
+// Equals reports whether the frozen set contains exactly the same elements as
+// the other set. Two frozen sets can be compared directly with ==.
+func (f FrozenSet[T]) Equals(other Set[T]) bool {
+	return equal(f.view(), other)
+}
+
+// IsSubsetOf reports whether every element of the frozen set is in the other
+// set (**X** ⊆ **Y**).
+func (f FrozenSet[T]) IsSubsetOf(other Set[T]) bool {
+	return isSubset(f.view(), other)
+}
+
+// IsSupersetOf reports whether every element of the other set is in the
+// frozen set (**X** ⊇ **Y**).
+func (f FrozenSet[T]) IsSupersetOf(other Set[T]) bool {
+	return isSubset(other, f.view())
+}
+
+// frozenSeed is shared by all frozen sets so that equal contents always hash
+// to the same value.
+var frozenSeed = maphash.MakeSeed()
+
+// contentHash returns a hash of the elements of s that does not depend on the
+// order in which they are visited.
+func contentHash[T comparable](s *hashSet[T]) uint64 {
+	// Each element hash is mixed before summing, so that sets differing in
+	// a few related elements do not cancel out.
+	hash := uint64(s.Cardinality())
+	for elem := range s.elements {
+		hash += mix64(maphash.Comparable(frozenSeed, elem))
+	}
+	return mix64(hash)
+}
+
+// mix64 is the finalizer of the SplitMix64 generator.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// internTable holds weak references to the canonical contents of every live
+// frozen set of one element type, grouped by content hash.
+type internTable[T comparable] struct {
+	mu      sync.Mutex
+	buckets map[uint64][]weak.Pointer[frozenData[T]]
+}
+
+// internTables maps each element type to its *internTable.
+var internTables sync.Map
+
+func internTableFor[T comparable]() *internTable[T] {
+	key := reflect.TypeFor[T]()
+	table, ok := internTables.Load(key)
+	if !ok {
+		table, _ = internTables.LoadOrStore(key, &internTable[T]{
+			buckets: make(map[uint64][]weak.Pointer[frozenData[T]]),
+		})
+	}
+	return table.(*internTable[T])
+}
+
+// intern returns the canonical contents equal to s, registering s as the
+// canonical contents if there are none yet.
+func (t *internTable[T]) intern(s *hashSet[T]) *frozenData[T] {
+	hash := contentHash(s)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, wp := range t.buckets[hash] {
+		if data := wp.Value(); data != nil && equal(&data.elements, s) {
+			return data
+		}
+	}
+
+	data := &frozenData[T]{elements: *s}
+	t.buckets[hash] = append(t.buckets[hash], weak.Make(data))
+	runtime.AddCleanup(data, t.sweep, hash)
+	return data
+}
+
+// sweep drops the references to collected contents with the given hash.
+func (t *internTable[T]) sweep(hash uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	live := t.buckets[hash][:0]
+	for _, wp := range t.buckets[hash] {
+		if wp.Value() != nil {
+			live = append(live, wp)
+		}
+	}
+	if len(live) == 0 {
+		delete(t.buckets, hash)
+	} else {
+		t.buckets[hash] = live
+	}
+}
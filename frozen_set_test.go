@@ -0,0 +1,128 @@
+package set
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFrozenSetEquality(t *testing.T) {
+	sorted := NewSortedSet[int]()
+	Insert(sorted, newMockSet(3, 2, 1).All())
+
+	tests := []struct {
+		name  string
+		a, b  FrozenSet[int]
+		equal bool
+	}{
+		{"same elements", NewFrozenSet(1, 2, 3), NewFrozenSet(3, 1, 2), true},
+		{"duplicates", NewFrozenSet(1, 1, 2), NewFrozenSet(2, 1), true},
+		{"from other implementations", Freeze[int](sorted), Freeze[int](newMockSet(1, 2, 3)), true},
+		{"zero value", FrozenSet[int]{}, NewFrozenSet[int](), true},
+		{"empty set", FrozenSet[int]{}, Freeze(NewHashSet[int]()), true},
+		{"different elements", NewFrozenSet(1, 2), NewFrozenSet(1, 3), false},
+		{"subset", NewFrozenSet(1, 2), NewFrozenSet(1, 2, 3), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a == tt.b; got != tt.equal {
+				t.Errorf("%v == %v is %v, want %v", tt.a, tt.b, got, tt.equal)
+			}
+			if got := tt.a.Equals(tt.b.Thaw()); got != tt.equal {
+				t.Errorf("%v.Equals(%v) = %v, want %v", tt.a, tt.b, got, tt.equal)
+			}
+		})
+	}
+}
+
+func TestFrozenSetAsElement(t *testing.T) {
+	family := NewHashSet[FrozenSet[string]]()
+	family.Insert(NewFrozenSet("a", "b"))
+	family.Insert(NewFrozenSet("b", "a"))
+	family.Insert(NewFrozenSet[string]())
+
+	if family.Cardinality() != 2 {
+		t.Errorf("family has %d members, want 2: %v", family.Cardinality(), family)
+	}
+	if !family.Contains(NewFrozenSet("a", "b")) {
+		t.Error("family should contain an equal frozen set")
+	}
+	if got := family.String(); got != "{{a, b}, {}}" && got != "{{}, {a, b}}" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Frozen sets nest to any depth.
+	nested := NewFrozenSet(NewFrozenSet(1), NewFrozenSet(1, 2))
+	if nested != NewFrozenSet(NewFrozenSet(2, 1), NewFrozenSet(1)) {
+		t.Error("nested frozen sets with equal contents should be equal")
+	}
+	if !nested.Contains(NewFrozenSet(1)) || nested.Contains(NewFrozenSet(2)) {
+		t.Error("Contains on nested frozen set is incorrect")
+	}
+}
+
+func TestFrozenSetIsImmutable(t *testing.T) {
+	source := NewHashSet[int]()
+	source.Insert(1)
+	frozen := Freeze(source)
+	source.Insert(2)
+
+	thawed := frozen.Thaw()
+	thawed.Insert(3)
+
+	if frozen != NewFrozenSet(1) || frozen.Cardinality() != 1 {
+		t.Errorf("frozen set changed to %v, want {1}", frozen)
+	}
+	if !frozen.IsSubsetOf(source) || !frozen.IsSupersetOf(newMockSet(1)) || frozen.IsSupersetOf(source) {
+		t.Error("subset relations are incorrect")
+	}
+	if frozen.IsEmpty() || !(FrozenSet[int]{}).IsEmpty() || len(frozen.ToSlice()) != 1 {
+		t.Error("IsEmpty or ToSlice is incorrect")
+	}
+}
+
+func TestFrozenSetConcurrentFreeze(t *testing.T) {
+	results := make([]FrozenSet[int], 16)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = NewFrozenSet(i%2, 10, 20)
+		}()
+	}
+	wg.Wait()
+
+	for i, f := range results {
+		if f != results[i%2] {
+			t.Errorf("concurrently frozen sets %d and %d differ", i, i%2)
+		}
+	}
+}
+
+// internTestElem gives the test below an intern table of its own.
+type internTestElem int
+
+func TestFrozenSetReleasesContents(t *testing.T) {
+	for i := range 100 {
+		_ = NewFrozenSet(internTestElem(i), internTestElem(i+1))
+	}
+	table := internTableFor[internTestElem]()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		runtime.GC()
+		table.mu.Lock()
+		remaining := len(table.buckets)
+		table.mu.Unlock()
+		if remaining == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d unreachable frozen sets are still interned", remaining)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,161 @@
+package set
+
+// The functions below transform and query sets through the Set interface.
+// They are top-level functions rather than methods because Go methods cannot
+// introduce type parameters, and a method returning Set[U] would create a
+// dependency cycle in the interface.
+//
+// Functions that build new sets return a set of the same implementation as
+// their argument where possible: filtering a SortedSet yields a SortedSet, and
+// mapping a ConcurrentSet yields a ConcurrentSet. Sets from other packages, and
+// implementations that cannot hold the new element type, yield a hash set.
+//
+// The callbacks must not modify the set they are applied to.
+
+// emptier is implemented by the sets of this package. emptyLike returns a new
+// empty set of the same implementation and configuration as the receiver.
+type emptier[T comparable] interface {
+	emptyLike() Set[T]
+}
+
+// emptyLike returns a new empty set of the same kind as s, or a hash set if s
+// is not one of the implementations of this package.
+func emptyLike[T comparable](s Set[T]) Set[T] {
+	if e, ok := s.(emptier[T]); ok {
+		return e.emptyLike()
+	}
+	return NewHashSet[T]()
+}
+
+// emptyMapped returns a new empty set of element type U of the same kind as
+// s, or a hash set if that kind cannot hold elements of type U.
+func emptyMapped[T, U comparable](s Set[T]) Set[U] {
+	switch s := s.(type) {
+	case *hashSet[T]:
+		return NewHashSet[U]()
+	case *ConcurrentSet[T]:
+		return NewConcurrentSet[U]()
+	case *shardedSet[T]:
+		return newShardedSet[U](len(s.shards))
+	case *LinkedHashSet[T]:
+		return NewLinkedHashSet[U]()
+	case *PersistentSet[T]:
+		return NewPersistentSet[U]()
+	}
+	// SortedSet, BitSet and RoaringBitmap constrain their element type, so
+	// they are only kept when U is the same type as T.
+	if r, ok := emptyLike(s).(Set[U]); ok {
+		return r
+	}
+	return NewHashSet[U]()
+}
+
+// Map returns a new set containing f(x) for every element x of s.
+// Elements that map to the same value are stored only once, so the result may
+// be smaller than s.
+func Map[T, U comparable](s Set[T], f func(T) U) Set[U] {
+	result := emptyMapped[T, U](s)
+	for elem := range s.All() {
+		result.Insert(f(elem))
+	}
+	return result
+}
+
+// Filter returns a new set containing the elements of s for which keep returns
+// true: {x ∈ **X** | keep(x)}.
+func Filter[T comparable](s Set[T], keep func(T) bool) Set[T] {
+	result := emptyLike(s)
+	for elem := range s.All() {
+		if keep(elem) {
+			result.Insert(elem)
+		}
+	}
+	return result
+}
+
+// Reduce folds the elements of s into a single value: starting from initial,
+// it replaces the accumulator with f(accumulator, x) for every element x. The
+// elements are visited in the order of s.All, so f should not depend on that
+// order unless s has a defined one.
+func Reduce[T comparable, A any](s Set[T], initial A, f func(A, T) A) A {
+	acc := initial
+	for elem := range s.All() {
+		acc = f(acc, elem)
+	}
+	return acc
+}
+
+// Any reports whether pred returns true for at least one element of s.
+// It stops at the first such element and returns false for the empty set.
+func Any[T comparable](s Set[T], pred func(T) bool) bool {
+	_, found := Find(s, pred)
+	return found
+}
+
+// All reports whether pred returns true for every element of s.
+// It stops at the first element for which pred returns false and returns true
+// for the empty set.
+func All[T comparable](s Set[T], pred func(T) bool) bool {
+	return !Any(s, func(elem T) bool { return !pred(elem) })
+}
+
+// None reports whether pred returns false for every element of s.
+// It returns true for the empty set.
+func None[T comparable](s Set[T], pred func(T) bool) bool {
+	return !Any(s, pred)
+}
+
+// Find returns an element of s for which pred returns true. The boolean is
+// false if there is no such element. If several elements match, the first one
+// yielded by s.All is returned.
+func Find[T comparable](s Set[T], pred func(T) bool) (T, bool) {
+	for elem := range s.All() {
+		if pred(elem) {
+			return elem, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Count returns the number of elements of s for which pred returns true.
+func Count[T comparable](s Set[T], pred func(T) bool) int {
+	count := 0
+	for elem := range s.All() {
+		if pred(elem) {
+			count++
+		}
+	}
+	return count
+}
+
+// Partition splits s into two new sets: the elements for which pred returns
+// true and the rest. The two sets are disjoint and their union is s.
+func Partition[T comparable](s Set[T], pred func(T) bool) (matching, rest Set[T]) {
+	matching, rest = emptyLike(s), emptyLike(s)
+	for elem := range s.All() {
+		if pred(elem) {
+			matching.Insert(elem)
+		} else {
+			rest.Insert(elem)
+		}
+	}
+	return matching, rest
+}
+
+// GroupBy splits s into new sets of the elements that share the same key.
+// The returned map holds one non-empty set for every distinct key; the sets
+// are pairwise disjoint and their union is s.
+func GroupBy[T, K comparable](s Set[T], key func(T) K) map[K]Set[T] {
+	groups := make(map[K]Set[T])
+	for elem := range s.All() {
+		k := key(elem)
+		group, ok := groups[k]
+		if !ok {
+			group = emptyLike(s)
+			groups[k] = group
+		}
+		group.Insert(elem)
+	}
+	return groups
+}
@@ -0,0 +1,168 @@
+package set
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestFunctionalCombinators(t *testing.T) {
+	s := newMockSet(1, 2, 3, 4, 5, 6)
+	even := func(x int) bool { return x%2 == 0 }
+	negative := func(x int) bool { return x < 0 }
+
+	if got := Map(s, func(x int) int { return x / 2 }); !got.Equals(newMockSet(0, 1, 2, 3)) {
+		t.Errorf("Map() = %v, want {0, 1, 2, 3}", got)
+	}
+	if got := Map(s, strconv.Itoa); !got.Equals(newMockSet("1", "2", "3", "4", "5", "6")) {
+		t.Errorf("Map(strconv.Itoa) = %v", got)
+	}
+	if got := Filter(s, even); !got.Equals(newMockSet(2, 4, 6)) {
+		t.Errorf("Filter() = %v, want {2, 4, 6}", got)
+	}
+	if got := Reduce(s, 0, func(sum, x int) int { return sum + x }); got != 21 {
+		t.Errorf("Reduce() = %d, want 21", got)
+	}
+	if got := Count(s, even); got != 3 {
+		t.Errorf("Count() = %d, want 3", got)
+	}
+	if elem, ok := Find(s, func(x int) bool { return x > 4 }); !ok || elem <= 4 {
+		t.Errorf("Find() = %d, %v, want an element greater than 4", elem, ok)
+	}
+	if elem, ok := Find(s, negative); ok || elem != 0 {
+		t.Errorf("Find() = %d, %v, want 0, false", elem, ok)
+	}
+
+	tests := []struct {
+		name     string
+		got      bool
+		expected bool
+	}{
+		{"Any(even)", Any(s, even), true},
+		{"Any(negative)", Any(s, negative), false},
+		{"All(even)", All(s, even), false},
+		{"All(not negative)", All(s, func(x int) bool { return !negative(x) }), true},
+		{"None(negative)", None(s, negative), true},
+		{"None(even)", None(s, even), false},
+		{"Any on empty set", Any(newMockSet[int](), even), false},
+		{"All on empty set", All(newMockSet[int](), negative), true},
+		{"None on empty set", None(newMockSet[int](), even), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.expected)
+			}
+		})
+	}
+
+	matching, rest := Partition(s, even)
+	if !matching.Equals(newMockSet(2, 4, 6)) || !rest.Equals(newMockSet(1, 3, 5)) {
+		t.Errorf("Partition() = %v, %v, want {2, 4, 6}, {1, 3, 5}", matching, rest)
+	}
+
+	groups := GroupBy(s, func(x int) int { return x % 3 })
+	if len(groups) != 3 {
+		t.Fatalf("GroupBy() returned %d groups, want 3", len(groups))
+	}
+	for k, want := range map[int]Set[int]{0: newMockSet(3, 6), 1: newMockSet(1, 4), 2: newMockSet(2, 5)} {
+		if !groups[k].Equals(want) {
+			t.Errorf("GroupBy()[%d] = %v, want %v", k, groups[k], want)
+		}
+	}
+	if groups := GroupBy(newMockSet[int](), even); len(groups) != 0 {
+		t.Errorf("GroupBy() of the empty set returned %d groups", len(groups))
+	}
+
+	// Find and Any stop at the first match.
+	visited := 0
+	Any(s, func(int) bool { visited++; return true })
+	if visited != 1 {
+		t.Errorf("Any() called pred %d times, want 1", visited)
+	}
+}
+
+func TestFunctionalCombinatorsPreserveKind(t *testing.T) {
+	fill := func(s Set[int]) Set[int] {
+		s.InsertAll(1, 2, 3, 4)
+		return s
+	}
+	even := func(x int) bool { return x%2 == 0 }
+
+	tests := []struct {
+		name string
+		set  Set[int]
+	}{
+		{"hashSet", fill(NewHashSet[int]())},
+		{"ConcurrentSet", fill(NewConcurrentSet[int]())},
+		{"shardedSet", fill(NewShardedSet[int](4))},
+		{"SortedSet", fill(NewSortedSet[int]())},
+		{"LinkedHashSet", fill(NewLinkedHashSet[int]())},
+		{"PersistentSet", fill(NewPersistentSet[int]())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind := fmt.Sprintf("%T", tt.set)
+			check := func(op string, got Set[int]) {
+				t.Helper()
+				if k := fmt.Sprintf("%T", got); k != kind {
+					t.Errorf("%s() returned %s, want %s", op, k, kind)
+				}
+			}
+			check("Map", Map(tt.set, func(x int) int { return x * 10 }))
+			check("Filter", Filter(tt.set, even))
+			matching, rest := Partition(tt.set, even)
+			check("Partition", matching)
+			check("Partition", rest)
+			for _, group := range GroupBy(tt.set, even) {
+				check("GroupBy", group)
+			}
+		})
+	}
+
+	if sh, ok := Map(fill(NewShardedSet[int](8)), strconv.Itoa).(*shardedSet[string]); !ok || len(sh.shards) != 8 {
+		t.Error("Map() of a shardedSet did not keep the number of shards")
+	}
+	// A SortedSet cannot be mapped to an arbitrary type, so a hash set is used
+	// unless the element type stays the same.
+	if got := Map(fill(NewSortedSet[int]()), func(x int) Pair[int] { return Pair[int]{x, x} }); !isHashSet(got) {
+		t.Errorf("Map() of a SortedSet to pairs returned %T", got)
+	}
+	b := NewBitSet()
+	b.InsertAll(1, 2, 3)
+	if _, ok := Map[uint](b, func(x uint) uint { return x * 2 }).(*BitSet); !ok {
+		t.Error("Map() of a BitSet to uint did not return a BitSet")
+	}
+	if _, ok := Filter[uint32](NewRoaringBitmap(), func(uint32) bool { return true }).(*RoaringBitmap); !ok {
+		t.Error("Filter() of a RoaringBitmap did not return a RoaringBitmap")
+	}
+	if !isHashSet(Filter[int](newMockSet(1, 2), even)) {
+		t.Error("Filter() of a foreign set did not return a hash set")
+	}
+}
+
+func TestFunctionalCombinatorsOrder(t *testing.T) {
+	l := newLinkedHashSet("pear", "fig", "apple", "kiwi")
+	if got := Map[string](l, func(s string) int { return len(s) }).ToSlice(); !slices.Equal(got, []int{4, 3, 5}) {
+		t.Errorf("Map() of a LinkedHashSet = %v, want insertion order [4 3 5]", got)
+	}
+	if got := Filter[string](l, func(s string) bool { return len(s) == 4 }).ToSlice(); !slices.Equal(got, []string{"pear", "kiwi"}) {
+		t.Errorf("Filter() of a LinkedHashSet = %v, want [pear kiwi]", got)
+	}
+
+	sorted := NewSortedSet[string]()
+	sorted.InsertAll("pear", "fig", "apple", "kiwi")
+	if got := Reduce[string](sorted, "", func(acc, s string) string { return acc + s[:1] }); got != "afkp" {
+		t.Errorf("Reduce() over a SortedSet = %q, want %q", got, "afkp")
+	}
+	if got, _ := Find[string](sorted, func(s string) bool { return len(s) == 4 }); got != "kiwi" {
+		t.Errorf("Find() in a SortedSet = %q, want the smallest match %q", got, "kiwi")
+	}
+}
+
+func isHashSet[T comparable](s Set[T]) bool {
+	_, ok := s.(*hashSet[T])
+	return ok
+}
@@ -2,8 +2,8 @@ package set
 
 import (
 	"fmt"
-	"sort"
-	"strings"
+	"iter"
+	"maps"
 )
 
 // hashSet implements the Set interface using a map for O(1) operations.
@@ -19,6 +19,10 @@ func NewHashSet[T comparable]() Set[T] {
 	}
 }
 
+func (h *hashSet[T]) emptyLike() Set[T] {
+	return NewHashSet[T]()
+}
+
 func (h *hashSet[T]) Insert(elem T) {
 	h.elements[elem] = struct{}{}
 }
@@ -27,6 +31,47 @@ func (h *hashSet[T]) Remove(elem T) {
 	delete(h.elements, elem)
 }
 
+func (h *hashSet[T]) InsertAll(elems ...T) int {
+	before := len(h.elements)
+	for _, elem := range elems {
+		h.elements[elem] = struct{}{}
+	}
+	return len(h.elements) - before
+}
+
+func (h *hashSet[T]) RemoveAll(elems ...T) int {
+	before := len(h.elements)
+	for _, elem := range elems {
+		delete(h.elements, elem)
+	}
+	return before - len(h.elements)
+}
+
+func (h *hashSet[T]) RetainIf(keep func(T) bool) int {
+	return h.RemoveIf(func(elem T) bool { return !keep(elem) })
+}
+
+func (h *hashSet[T]) RemoveIf(remove func(T) bool) int {
+	before := len(h.elements)
+	maps.DeleteFunc(h.elements, func(elem T, _ struct{}) bool { return remove(elem) })
+	return before - len(h.elements)
+}
+
+func (h *hashSet[T]) Clear() int {
+	n := len(h.elements)
+	clear(h.elements)
+	return n
+}
+
+func (h *hashSet[T]) Pop() (T, bool) {
+	for elem := range h.elements {
+		delete(h.elements, elem)
+		return elem, true
+	}
+	var zero T
+	return zero, false
+}
+
 func (h *hashSet[T]) Contains(elem T) bool {
 	_, exists := h.elements[elem]
 	return exists
@@ -40,6 +85,16 @@ func (h *hashSet[T]) IsEmpty() bool {
 	return len(h.elements) == 0
 }
 
+func (h *hashSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for elem := range h.elements {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
 func (h *hashSet[T]) ToSlice() []T {
 	result := make([]T, 0, len(h.elements))
 	for elem := range h.elements {
@@ -49,50 +104,21 @@ func (h *hashSet[T]) ToSlice() []T {
 }
 
 func (h *hashSet[T]) String() string {
-	if h.IsEmpty() {
-		return "{}"
-	}
-
-	elems := h.ToSlice()
-
-	// We'll sort the elements if they are strings.
-	if len(elems) > 0 {
-		if _, ok := any(elems[0]).(string); ok {
-			strElems := make([]string, len(elems))
-			for i, v := range elems {
-				strElems[i] = any(v).(string)
-			}
-			sort.Strings(strElems)
-			for i, v := range strElems {
-				elems[i] = any(v).(T)
-			}
-		}
-	}
+	return formatLiteral(sortForDisplay(h.ToSlice()), formatElement)
+}
 
-	var sb strings.Builder
-	sb.WriteString("{")
-	for i, elem := range elems {
-		if i > 0 {
-			sb.WriteString(", ")
-		}
-		sb.WriteString(fmt.Sprintf("%v", elem))
-	}
-	sb.WriteString("}")
-	return sb.String()
+// Format implements fmt.Formatter.
+func (h *hashSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, sortForDisplay(h.ToSlice()), "set.NewHashSet["+typeArg[T]()+"]()", "set.Set["+typeArg[T]()+"]")
 }
 
 func (h *hashSet[T]) Equals(other Set[T]) bool {
-	otherSet, ok := other.(*hashSet[T])
-	if !ok {
-		panic("Operation only supported between hashSets")
-	}
-
-	if h.Cardinality() != otherSet.Cardinality() {
+	if h.Cardinality() != other.Cardinality() {
 		return false
 	}
 
 	for elem := range h.elements {
-		if !otherSet.Contains(elem) {
+		if !other.Contains(elem) {
 			return false
 		}
 	}
@@ -100,17 +126,12 @@ func (h *hashSet[T]) Equals(other Set[T]) bool {
 }
 
 func (h *hashSet[T]) IsSubsetOf(other Set[T]) bool {
-	otherSet, ok := other.(*hashSet[T])
-	if !ok {
-		panic("Operation only supported between hashSets")
-	}
-
-	if h.Cardinality() > otherSet.Cardinality() {
+	if h.Cardinality() > other.Cardinality() {
 		return false
 	}
 
 	for elem := range h.elements {
-		if !otherSet.Contains(elem) {
+		if !other.Contains(elem) {
 			return false
 		}
 	}
@@ -118,71 +139,64 @@ func (h *hashSet[T]) IsSubsetOf(other Set[T]) bool {
 }
 
 func (h *hashSet[T]) IsSupersetOf(other Set[T]) bool {
-	otherSet, ok := other.(*hashSet[T])
-	if !ok {
-		panic("Operation only supported between hashSets")
+	if h.Cardinality() < other.Cardinality() {
+		return false
+	}
+
+	for elem := range other.All() {
+		if !h.Contains(elem) {
+			return false
+		}
 	}
-	return otherSet.IsSubsetOf(h)
+	return true
 }
 
 func (h *hashSet[T]) IsProperSubsetOf(other Set[T]) bool {
-	return h.Cardinality() < other.(*hashSet[T]).Cardinality() && h.IsSubsetOf(other)
+	return h.Cardinality() < other.Cardinality() && h.IsSubsetOf(other)
 }
 
 func (h *hashSet[T]) IsProperSupersetOf(other Set[T]) bool {
-	return h.Cardinality() > other.(*hashSet[T]).Cardinality() && h.IsSupersetOf(other)
+	return h.Cardinality() > other.Cardinality() && h.IsSupersetOf(other)
 }
 
 func (h *hashSet[T]) Union(other Set[T]) Set[T] {
-	otherSet, ok := other.(*hashSet[T])
-	if !ok {
-		panic("Operation only supported between hashSets")
+	unionSet := &hashSet[T]{
+		elements: make(map[T]struct{}, max(h.Cardinality(), other.Cardinality())),
 	}
-
-	unionSet := NewHashSet[T]()
 	for elem := range h.elements {
 		unionSet.Insert(elem)
 	}
-	for elem := range otherSet.elements {
+	for elem := range other.All() {
 		unionSet.Insert(elem)
 	}
 	return unionSet
 }
 
 func (h *hashSet[T]) Intersection(other Set[T]) Set[T] {
-	otherSet, ok := other.(*hashSet[T])
-	if !ok {
-		panic("Operation only supported between hashSets")
-	}
-
-	cardinality1 := h.Cardinality()
-	cardinality2 := otherSet.Cardinality()
-
 	intersectionSet := NewHashSet[T]()
 
-	smaller, larger := h, otherSet
-	if cardinality1 > cardinality2 {
-		smaller, larger = otherSet, h
+	// Iterate over the smaller set and probe the larger one.
+	if h.Cardinality() <= other.Cardinality() {
+		for elem := range h.elements {
+			if other.Contains(elem) {
+				intersectionSet.Insert(elem)
+			}
+		}
+		return intersectionSet
 	}
 
-	for elem := range smaller.elements {
-		if larger.Contains(elem) {
+	for elem := range other.All() {
+		if h.Contains(elem) {
 			intersectionSet.Insert(elem)
 		}
 	}
-
 	return intersectionSet
 }
 
 func (h *hashSet[T]) Difference(other Set[T]) Set[T] {
-	otherSet, ok := other.(*hashSet[T])
-	if !ok {
-		panic("Operation only supported between hashSets")
-	}
-
 	differenceSet := NewHashSet[T]()
 	for elem := range h.elements {
-		if !otherSet.Contains(elem) {
+		if !other.Contains(elem) {
 			differenceSet.Insert(elem)
 		}
 	}
@@ -190,27 +204,60 @@ func (h *hashSet[T]) Difference(other Set[T]) Set[T] {
 }
 
 func (h *hashSet[T]) SymmetricDifference(other Set[T]) Set[T] {
-	otherSet, ok := other.(*hashSet[T])
-	if !ok {
-		panic("Operation only supported between hashSets")
-	}
-
 	// Simple implementation:
-	// difference1 := h.Difference(otherSet)
-	// difference2 := otherSet.Difference(h)
+	// difference1 := h.Difference(other)
+	// difference2 := other.Difference(h)
 	// return difference1.Union(difference2)
 
 	// One-pass implementation:
 	symmetricDifferenceSet := NewHashSet[T]()
 	for elem := range h.elements {
-		if !otherSet.Contains(elem) {
+		if !other.Contains(elem) {
 			symmetricDifferenceSet.Insert(elem)
 		}
 	}
-	for elem := range otherSet.elements {
+	for elem := range other.All() {
 		if !h.Contains(elem) {
 			symmetricDifferenceSet.Insert(elem)
 		}
 	}
 	return symmetricDifferenceSet
 }
+
+func (h *hashSet[T]) UnionWith(other Set[T]) int {
+	before := len(h.elements)
+	for elem := range other.All() {
+		h.elements[elem] = struct{}{}
+	}
+	return len(h.elements) - before
+}
+
+func (h *hashSet[T]) IntersectWith(other Set[T]) int {
+	return h.RemoveIf(func(elem T) bool { return !other.Contains(elem) })
+}
+
+func (h *hashSet[T]) DifferenceWith(other Set[T]) int {
+	if other.Cardinality() >= len(h.elements) {
+		return h.RemoveIf(other.Contains)
+	}
+	before := len(h.elements)
+	for elem := range other.All() {
+		delete(h.elements, elem)
+	}
+	return before - len(h.elements)
+}
+
+func (h *hashSet[T]) SymmetricDifferenceWith(other Set[T]) int {
+	// Deleting during the loop is safe even when other is h itself, as Go
+	// allows deleting map entries while ranging over the map.
+	toggled := 0
+	for elem := range other.All() {
+		if _, exists := h.elements[elem]; exists {
+			delete(h.elements, elem)
+		} else {
+			h.elements[elem] = struct{}{}
+		}
+		toggled++
+	}
+	return toggled
+}
@@ -0,0 +1,19 @@
+package set
+
+import "iter"
+
+// Collect returns a new set containing the elements yielded by seq.
+// Duplicate elements in the sequence are stored only once.
+func Collect[T comparable](seq iter.Seq[T]) Set[T] {
+	s := NewHashSet[T]()
+	Insert(s, seq)
+	return s
+}
+
+// Insert adds every element yielded by seq to the set s.
+// Elements that already exist in s leave it unchanged.
+func Insert[T comparable](s Set[T], seq iter.Seq[T]) {
+	for elem := range seq {
+		s.Insert(elem)
+	}
+}
@@ -0,0 +1,244 @@
+package set
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Sets are encoded in JSON as arrays of their elements. Sets without an order
+// of their own, such as hashSet, ConcurrentSet and PersistentSet, sort the
+// array for deterministic output: by value for element types satisfying
+// cmp.Ordered, and by the encoding of each element otherwise. SortedSet,
+// BitSet and RoaringBitmap encode in ascending order and LinkedHashSet in
+// insertion order.
+//
+// Decoding replaces the contents of the set with the elements of the array,
+// storing repeated elements once. Use DecodeJSON to reject them instead. The
+// JSON null leaves the set unchanged, as for other types implementing
+// json.Unmarshaler.
+//
+// The encoding/json package can only decode into a Set[T] interface value,
+// such as a struct field, that already holds a set; a nil interface cannot be
+// decoded into because the implementation to create is unknown. Fields and
+// map values of a concrete type, such as *SortedSet[T], have no such
+// restriction.
+
+// DuplicatePolicy controls how DecodeJSON treats a JSON array that contains
+// the same element more than once.
+type DuplicatePolicy int
+
+const (
+	// IgnoreDuplicates stores repeated elements once, as Insert does. This is
+	// the policy of the UnmarshalJSON methods.
+	IgnoreDuplicates DuplicatePolicy = iota
+
+	// RejectDuplicates fails with an error wrapping ErrDuplicateElement.
+	RejectDuplicates
+)
+
+// DecodeJSON replaces the contents of s with the elements of the JSON array in
+// data, treating repeated elements according to policy. On error, s is left
+// unchanged; the error wraps ErrNilSet if s is nil, and ErrTooLarge if s is a
+// BitSet and an element is too large for it.
+func DecodeJSON[T comparable](data []byte, s Set[T], policy DuplicatePolicy) error {
+	if isNil(s) {
+		return fmt.Errorf("%w: cannot decode into %T", ErrNilSet, s)
+	}
+	elems, err := decodeJSONElements[T](data, policy)
+	if err != nil || elems == nil {
+		return err
+	}
+	if err := checkDecodedElements(s, elems, len(data)); err != nil {
+		return err
+	}
+	replaceElements(s, elems)
+	return nil
+}
+
+// replaceElements replaces the contents of s with elems. The replacement is
+// atomic for a ConcurrentSet.
+func replaceElements[T comparable](s Set[T], elems []T) {
+	if c, ok := s.(*ConcurrentSet[T]); ok {
+		c.replace(elems)
+		return
+	}
+	s.Clear()
+	s.InsertAll(elems...)
+}
+
+// decodeJSONElements decodes the JSON array in data. It returns nil, without
+// an error, for the JSON null.
+func decodeJSONElements[T comparable](data []byte, policy DuplicatePolicy) ([]T, error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil, nil
+	}
+	elems := []T{}
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return nil, err
+	}
+	if policy == RejectDuplicates {
+		seen := make(map[T]struct{}, len(elems))
+		for i, elem := range elems {
+			if _, exists := seen[elem]; exists {
+				return nil, fmt.Errorf("%w: %v at index %d", ErrDuplicateElement, elem, i)
+			}
+			seen[elem] = struct{}{}
+		}
+	}
+	return elems, nil
+}
+
+// marshalJSON encodes elems as a JSON array. If sorted is true, elems is
+// sorted first, by value for ordered element types and by encoding otherwise.
+func marshalJSON[T comparable](elems []T, sorted bool) ([]byte, error) {
+	compare := orderedCompare[T]()
+	if sorted && compare != nil {
+		slices.SortFunc(elems, compare)
+	}
+
+	encoded := make([]json.RawMessage, len(elems))
+	for i, elem := range elems {
+		var err error
+		if encoded[i], err = json.Marshal(elem); err != nil {
+			return nil, err
+		}
+	}
+	if sorted && compare == nil {
+		slices.SortFunc(encoded, func(a, b json.RawMessage) int { return bytes.Compare(a, b) })
+	}
+	return json.Marshal(encoded)
+}
+
+// MarshalJSON encodes the set as a sorted JSON array.
+func (h *hashSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(h.ToSlice(), true)
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array.
+func (h *hashSet[T]) UnmarshalJSON(data []byte) error {
+	elems, err := decodeJSONElements[T](data, IgnoreDuplicates)
+	if err != nil || elems == nil {
+		return err
+	}
+	h.elements = make(map[T]struct{}, len(elems))
+	for _, elem := range elems {
+		h.elements[elem] = struct{}{}
+	}
+	return nil
+}
+
+// MarshalJSON encodes a snapshot of the set as a sorted JSON array.
+func (c *ConcurrentSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(c.ToSlice(), true)
+}
+
+// UnmarshalJSON atomically replaces the contents of the set with the elements
+// of a JSON array.
+func (c *ConcurrentSet[T]) UnmarshalJSON(data []byte) error {
+	elems, err := decodeJSONElements[T](data, IgnoreDuplicates)
+	if err != nil || elems == nil {
+		return err
+	}
+	c.replace(elems)
+	return nil
+}
+
+// replace atomically replaces the contents of c with elems. It also
+// initializes a ConcurrentSet allocated by encoding/json rather than by
+// NewConcurrentSet.
+func (c *ConcurrentSet[T]) replace(elems []T) {
+	elements := make(map[T]struct{}, len(elems))
+	for _, elem := range elems {
+		elements[elem] = struct{}{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.id == 0 {
+		c.id = concurrentSetIDs.Add(1)
+	}
+	c.elements.elements = elements
+}
+
+// MarshalJSON encodes a snapshot of the set as a sorted JSON array.
+func (s *shardedSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.ToSlice(), true)
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array. Like the other bulk operations, it is not atomic.
+func (s *shardedSet[T]) UnmarshalJSON(data []byte) error {
+	return DecodeJSON[T](data, s, IgnoreDuplicates)
+}
+
+// MarshalJSON encodes the set as a JSON array in ascending order.
+func (s *SortedSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s.ToSlice(), false)
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array.
+func (s *SortedSet[T]) UnmarshalJSON(data []byte) error {
+	return DecodeJSON[T](data, s, IgnoreDuplicates)
+}
+
+// MarshalJSON encodes the set as a JSON array in insertion order.
+func (l *LinkedHashSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(l.ToSlice(), false)
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array, in the order of their first occurrence.
+func (l *LinkedHashSet[T]) UnmarshalJSON(data []byte) error {
+	return DecodeJSON[T](data, l, IgnoreDuplicates)
+}
+
+// MarshalJSON encodes the set as a sorted JSON array.
+func (p *PersistentSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p.ToSlice(), true)
+}
+
+// UnmarshalJSON replaces the receiver with a version holding the elements of
+// a JSON array. Other versions are not affected.
+func (p *PersistentSet[T]) UnmarshalJSON(data []byte) error {
+	return DecodeJSON[T](data, p, IgnoreDuplicates)
+}
+
+// MarshalJSON encodes the set as a JSON array in ascending order.
+func (b *BitSet) MarshalJSON() ([]byte, error) {
+	return marshalJSON(b.ToSlice(), false)
+}
+
+// UnmarshalJSON replaces the contents of the set with the elements of a JSON
+// array.
+func (b *BitSet) UnmarshalJSON(data []byte) error {
+	return DecodeJSON[uint](data, b, IgnoreDuplicates)
+}
+
+// MarshalJSON encodes the bitmap as a JSON array in ascending order.
+func (r *RoaringBitmap) MarshalJSON() ([]byte, error) {
+	return marshalJSON(r.ToSlice(), false)
+}
+
+// UnmarshalJSON replaces the contents of the bitmap with the elements of a
+// JSON array.
+func (r *RoaringBitmap) UnmarshalJSON(data []byte) error {
+	return DecodeJSON[uint32](data, r, IgnoreDuplicates)
+}
+
+// MarshalJSON encodes the set as a sorted JSON array.
+func (f FrozenSet[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(f.ToSlice(), true)
+}
+
+// UnmarshalJSON sets f to the frozen set of the elements of a JSON array.
+func (f *FrozenSet[T]) UnmarshalJSON(data []byte) error {
+	elems, err := decodeJSONElements[T](data, IgnoreDuplicates)
+	if err != nil || elems == nil {
+		return err
+	}
+	*f = NewFrozenSet(elems...)
+	return nil
+}
@@ -0,0 +1,197 @@
+package set
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		testJSONRoundTrip(t, []string{"pear", "fig", "apple"}, `["apple","fig","pear"]`, []func() Set[string]{
+			NewHashSet[string],
+			func() Set[string] { return NewConcurrentSet[string]() },
+			func() Set[string] { return NewShardedSet[string](4) },
+			func() Set[string] { return NewSortedSet[string]() },
+			func() Set[string] { return NewPersistentSet[string]() },
+		})
+	})
+	t.Run("int", func(t *testing.T) {
+		testJSONRoundTrip(t, []int{10, -3, 2, 7}, `[-3,2,7,10]`, []func() Set[int]{
+			NewHashSet[int],
+			func() Set[int] { return NewConcurrentSet[int]() },
+			func() Set[int] { return NewShardedSet[int](4) },
+			func() Set[int] { return NewSortedSet[int]() },
+			func() Set[int] { return NewPersistentSet[int]() },
+		})
+	})
+	t.Run("Pair", func(t *testing.T) {
+		// Pairs are not ordered, so they are sorted by their encoding.
+		pairs := []Pair2[string, int]{{"b", 1}, {"a", 2}, {"a", 10}}
+		want := `[{"First":"a","Second":10},{"First":"a","Second":2},{"First":"b","Second":1}]`
+		testJSONRoundTrip(t, pairs, want, []func() Set[Pair2[string, int]]{
+			NewHashSet[Pair2[string, int]],
+			func() Set[Pair2[string, int]] { return NewConcurrentSet[Pair2[string, int]]() },
+			func() Set[Pair2[string, int]] { return NewPersistentSet[Pair2[string, int]]() },
+		})
+	})
+	t.Run("uint", func(t *testing.T) {
+		testJSONRoundTrip(t, []uint{130, 5, 64}, `[5,64,130]`, []func() Set[uint]{
+			func() Set[uint] { return NewBitSet() },
+		})
+	})
+	t.Run("uint32", func(t *testing.T) {
+		testJSONRoundTrip(t, []uint32{1 << 20, 5, 70000}, `[5,70000,1048576]`, []func() Set[uint32]{
+			func() Set[uint32] { return NewRoaringBitmap() },
+		})
+	})
+}
+
+func testJSONRoundTrip[T comparable](t *testing.T, elems []T, want string, constructors []func() Set[T]) {
+	t.Helper()
+	for _, newSet := range constructors {
+		s := newSet()
+		s.InsertAll(elems...)
+		data, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("json.Marshal(%T) error = %v", s, err)
+		}
+		if string(data) != want {
+			t.Errorf("json.Marshal(%T) = %s, want %s", s, data, want)
+		}
+
+		decoded := newSet()
+		decoded.Insert(elems[0]) // replaced by decoding
+		if err := json.Unmarshal(data, decoded); err != nil {
+			t.Fatalf("json.Unmarshal(%T) error = %v", decoded, err)
+		}
+		if !decoded.Equals(s) {
+			t.Errorf("%T round trip = %v, want %v", s, decoded, s)
+		}
+
+		empty, err := json.Marshal(newSet())
+		if err != nil || string(empty) != "[]" {
+			t.Errorf("json.Marshal(empty %T) = %s, %v, want []", s, empty, err)
+		}
+	}
+}
+
+func TestJSONLinkedHashSetOrder(t *testing.T) {
+	l := newLinkedHashSet("pear", "fig", "apple")
+	data, err := json.Marshal(l)
+	if err != nil || string(data) != `["pear","fig","apple"]` {
+		t.Errorf("json.Marshal() = %s, %v, want insertion order", data, err)
+	}
+
+	decoded := NewLinkedHashSet[string]()
+	if err := json.Unmarshal([]byte(`["kiwi","fig","kiwi","apple"]`), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.ToSlice(); !slices.Equal(got, []string{"kiwi", "fig", "apple"}) {
+		t.Errorf("json.Unmarshal() order = %v, want [kiwi fig apple]", got)
+	}
+}
+
+func TestJSONNested(t *testing.T) {
+	type document struct {
+		Tags    Set[string]                       `json:"tags"`
+		Scores  *SortedSet[int]                   `json:"scores"`
+		Owners  *ConcurrentSet[string]            `json:"owners"`
+		Frozen  FrozenSet[int]                    `json:"frozen"`
+		ByGroup map[string]*LinkedHashSet[string] `json:"by_group"`
+	}
+
+	in := document{
+		Tags:    NewHashSet[string](),
+		Scores:  NewSortedSet[int](),
+		Owners:  NewConcurrentSet[string](),
+		Frozen:  NewFrozenSet(3, 1, 2),
+		ByGroup: map[string]*LinkedHashSet[string]{"admins": newLinkedHashSet("root", "alice")},
+	}
+	in.Tags.InsertAll("go", "sets")
+	in.Scores.InsertAll(90, 75)
+	in.Owners.Insert("bob")
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"tags":["go","sets"],"scores":[75,90],"owners":["bob"],"frozen":[1,2,3],"by_group":{"admins":["root","alice"]}}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	// Tags is an interface and must hold a set; the other fields are
+	// allocated by encoding/json.
+	out := document{Tags: NewHashSet[string]()}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Tags.Equals(in.Tags) || !out.Scores.Equals(in.Scores) || !out.Owners.Equals(in.Owners) ||
+		out.Frozen != in.Frozen || !out.ByGroup["admins"].Equals(in.ByGroup["admins"]) {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+	// A ConcurrentSet allocated by encoding/json is fully usable.
+	if out.Owners.UnionWith(in.Owners) != 0 || out.Owners.id == 0 {
+		t.Error("decoded ConcurrentSet was not initialized")
+	}
+
+	var missing document
+	if err := json.Unmarshal(data, &missing); err == nil {
+		t.Error("json.Unmarshal() into a nil Set interface succeeded")
+	}
+}
+
+func TestJSONDecoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		policy   DuplicatePolicy
+		expected []int
+		err      error
+	}{
+		{"duplicates ignored", `[1, 2, 2, 3]`, IgnoreDuplicates, []int{1, 2, 3}, nil},
+		{"duplicates rejected", `[1, 2, 2, 3]`, RejectDuplicates, []int{7}, ErrDuplicateElement},
+		{"no duplicates", `[3, 1]`, RejectDuplicates, []int{1, 3}, nil},
+		{"empty array", `[]`, RejectDuplicates, nil, nil},
+		{"null", ` null `, IgnoreDuplicates, []int{7}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSortedSet[int]()
+			s.Insert(7)
+			err := DecodeJSON[int]([]byte(tt.data), s, tt.policy)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("DecodeJSON() error = %v, want %v", err, tt.err)
+			}
+			if got := s.ToSlice(); !slices.Equal(got, tt.expected) {
+				t.Errorf("DecodeJSON() set = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	for _, data := range []string{`{"a": 1}`, `["x"]`, `[1,`, `[-1]`} {
+		b := NewBitSet()
+		b.Insert(7)
+		if err := json.Unmarshal([]byte(data), b); err == nil {
+			t.Errorf("json.Unmarshal(%s) succeeded", data)
+		}
+		if !b.Equals(newMockSet[uint](7)) {
+			t.Errorf("failed json.Unmarshal(%s) modified the set to %v", data, b)
+		}
+	}
+
+	// On 32-bit platforms encoding/json already rejects the element as a uint.
+	if b := NewBitSet(); strconv.IntSize == 64 {
+		if err := json.Unmarshal([]byte("[1, 18446744073709551615]"), b); !errors.Is(err, ErrTooLarge) || !b.IsEmpty() {
+			t.Errorf("json.Unmarshal() of a huge BitSet element error = %v, want ErrTooLarge", err)
+		}
+	}
+
+	if err := DecodeJSON[int]([]byte(`[1]`), nil, IgnoreDuplicates); !errors.Is(err, ErrNilSet) {
+		t.Errorf("DecodeJSON() into nil error = %v, want ErrNilSet", err)
+	}
+}
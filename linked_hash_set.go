@@ -0,0 +1,288 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+)
+
+// linkedNode is an element of the doubly linked list threading through a
+// LinkedHashSet in insertion order.
+type linkedNode[T comparable] struct {
+	value      T
+	prev, next *linkedNode[T]
+}
+
+// LinkedHashSet implements the Set interface with a map for O(1) membership
+// and a doubly linked list that remembers the order in which elements were
+// first inserted. Iteration, ToSlice and String all follow that order, so
+// their output is stable between calls.
+//
+// Re-inserting an element that is already present does not change its
+// position; use MoveToFront or MoveToBack to reorder elements.
+//
+// The zero value of a LinkedHashSet is an empty set ready to use.
+// A LinkedHashSet must not be copied after first use.
+type LinkedHashSet[T comparable] struct {
+	nodes map[T]*linkedNode[T]
+
+	// root is a sentinel: root.next is the first element and root.prev is
+	// the last, which removes the special cases for the ends of the list.
+	root linkedNode[T]
+}
+
+// NewLinkedHashSet creates and returns a new empty insertion-ordered set.
+func NewLinkedHashSet[T comparable]() *LinkedHashSet[T] {
+	l := &LinkedHashSet[T]{}
+	l.lazyInit()
+	return l
+}
+
+func (l *LinkedHashSet[T]) emptyLike() Set[T] {
+	return NewLinkedHashSet[T]()
+}
+
+func (l *LinkedHashSet[T]) lazyInit() {
+	if l.nodes == nil {
+		l.nodes = make(map[T]*linkedNode[T])
+		l.root.next = &l.root
+		l.root.prev = &l.root
+	}
+}
+
+// unlink removes n from the list.
+func (l *LinkedHashSet[T]) unlink(n *linkedNode[T]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+}
+
+// linkAfter inserts n into the list directly after at.
+func (l *LinkedHashSet[T]) linkAfter(n, at *linkedNode[T]) {
+	n.prev = at
+	n.next = at.next
+	at.next.prev = n
+	at.next = n
+}
+
+// Insert adds the element to the end of the set.
+// If the element already exists, the set and its order remain unchanged.
+func (l *LinkedHashSet[T]) Insert(elem T) {
+	l.lazyInit()
+	if _, exists := l.nodes[elem]; exists {
+		return
+	}
+	n := &linkedNode[T]{value: elem}
+	l.nodes[elem] = n
+	l.linkAfter(n, l.root.prev)
+}
+
+func (l *LinkedHashSet[T]) Remove(elem T) {
+	if n, exists := l.nodes[elem]; exists {
+		l.unlink(n)
+		delete(l.nodes, elem)
+	}
+}
+
+// InsertAll adds the elements that are not yet present to the end of the set,
+// in argument order, and returns how many were added.
+func (l *LinkedHashSet[T]) InsertAll(elems ...T) int {
+	before := len(l.nodes)
+	for _, elem := range elems {
+		l.Insert(elem)
+	}
+	return len(l.nodes) - before
+}
+
+func (l *LinkedHashSet[T]) RemoveAll(elems ...T) int {
+	before := len(l.nodes)
+	for _, elem := range elems {
+		l.Remove(elem)
+	}
+	return before - len(l.nodes)
+}
+
+// RetainIf deletes every element for which keep returns false and returns the
+// number of elements deleted. keep is called in insertion order.
+func (l *LinkedHashSet[T]) RetainIf(keep func(T) bool) int {
+	return l.RemoveIf(func(elem T) bool { return !keep(elem) })
+}
+
+// RemoveIf deletes every element for which remove returns true and returns the
+// number of elements deleted. remove is called in insertion order.
+func (l *LinkedHashSet[T]) RemoveIf(remove func(T) bool) int {
+	before := len(l.nodes)
+	for elem := range l.All() {
+		if remove(elem) {
+			l.Remove(elem)
+		}
+	}
+	return before - len(l.nodes)
+}
+
+func (l *LinkedHashSet[T]) Clear() int {
+	n := len(l.nodes)
+	l.nodes = nil
+	l.root = linkedNode[T]{}
+	return n
+}
+
+// Pop deletes the first element in insertion order and returns it.
+// The boolean is false if the set is empty.
+func (l *LinkedHashSet[T]) Pop() (T, bool) {
+	if len(l.nodes) == 0 {
+		var zero T
+		return zero, false
+	}
+	elem := l.root.next.value
+	l.Remove(elem)
+	return elem, true
+}
+
+func (l *LinkedHashSet[T]) Contains(elem T) bool {
+	_, exists := l.nodes[elem]
+	return exists
+}
+
+func (l *LinkedHashSet[T]) Cardinality() int {
+	return len(l.nodes)
+}
+
+func (l *LinkedHashSet[T]) IsEmpty() bool {
+	return len(l.nodes) == 0
+}
+
+// MoveToFront moves the element to the start of the iteration order and
+// reports whether it is in the set.
+func (l *LinkedHashSet[T]) MoveToFront(elem T) bool {
+	n, exists := l.nodes[elem]
+	if exists {
+		l.unlink(n)
+		l.linkAfter(n, &l.root)
+	}
+	return exists
+}
+
+// MoveToBack moves the element to the end of the iteration order and reports
+// whether it is in the set.
+func (l *LinkedHashSet[T]) MoveToBack(elem T) bool {
+	n, exists := l.nodes[elem]
+	if exists {
+		l.unlink(n)
+		l.linkAfter(n, l.root.prev)
+	}
+	return exists
+}
+
+// All returns an iterator over the elements of the set in insertion order.
+// The element being visited may be removed or moved during iteration.
+func (l *LinkedHashSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if l.nodes == nil {
+			return
+		}
+		for n := l.root.next; n != &l.root; {
+			next := n.next
+			if !yield(n.value) {
+				return
+			}
+			n = next
+		}
+	}
+}
+
+// ToSlice returns a slice containing all elements in the set in insertion order.
+func (l *LinkedHashSet[T]) ToSlice() []T {
+	result := make([]T, 0, len(l.nodes))
+	for elem := range l.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+// String returns a string representation of the set with its elements in
+// insertion order. Unlike most other implementations, the elements are not sorted.
+func (l *LinkedHashSet[T]) String() string {
+	return formatLiteral(l.ToSlice(), formatElement)
+}
+
+// Format implements fmt.Formatter, listing the elements in insertion order.
+func (l *LinkedHashSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, l.ToSlice(), "set.NewLinkedHashSet["+typeArg[T]()+"]()", "*set.LinkedHashSet["+typeArg[T]()+"]")
+}
+
+// Equals reports whether this set contains exactly the same elements as the
+// other set. The order of the elements is not taken into account.
+func (l *LinkedHashSet[T]) Equals(other Set[T]) bool {
+	return equal(l, other)
+}
+
+func (l *LinkedHashSet[T]) IsSubsetOf(other Set[T]) bool {
+	return isSubset(l, other)
+}
+
+func (l *LinkedHashSet[T]) IsSupersetOf(other Set[T]) bool {
+	return isSubset(other, l)
+}
+
+func (l *LinkedHashSet[T]) IsProperSubsetOf(other Set[T]) bool {
+	return l.Cardinality() < other.Cardinality() && l.IsSubsetOf(other)
+}
+
+func (l *LinkedHashSet[T]) IsProperSupersetOf(other Set[T]) bool {
+	return l.Cardinality() > other.Cardinality() && l.IsSupersetOf(other)
+}
+
+// Union returns a new LinkedHashSet containing all elements that are in either
+// this set or the other set (**X** ∪ **Y**). The elements of this set come
+// first, in their order, followed by the remaining elements of the other set
+// in its iteration order.
+func (l *LinkedHashSet[T]) Union(other Set[T]) Set[T] {
+	return unionInto(NewLinkedHashSet[T](), l, other)
+}
+
+// Intersection returns a new LinkedHashSet containing all elements that are in
+// both this set and the other set (**X** ∩ **Y**), in the order of this set.
+func (l *LinkedHashSet[T]) Intersection(other Set[T]) Set[T] {
+	result := NewLinkedHashSet[T]()
+	for elem := range l.All() {
+		if other.Contains(elem) {
+			result.Insert(elem)
+		}
+	}
+	return result
+}
+
+// Difference returns a new LinkedHashSet containing all elements that are in
+// this set but not in the other set (**X** \ **Y**), in the order of this set.
+func (l *LinkedHashSet[T]) Difference(other Set[T]) Set[T] {
+	return differenceInto(NewLinkedHashSet[T](), l, other)
+}
+
+// SymmetricDifference returns a new LinkedHashSet containing all elements that
+// are in either this set or the other set, but not in both (**X** Δ **Y**).
+// The elements of this set come first, in their order, followed by those of
+// the other set in its iteration order.
+func (l *LinkedHashSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return symmetricDifferenceInto(NewLinkedHashSet[T](), l, other)
+}
+
+// UnionWith adds the elements of the other set that are not yet present to the
+// end of this set, in the iteration order of the other set
+// (**X** ← **X** ∪ **Y**), and returns the number of elements added.
+func (l *LinkedHashSet[T]) UnionWith(other Set[T]) int {
+	return insertAll(l, other.All())
+}
+
+func (l *LinkedHashSet[T]) IntersectWith(other Set[T]) int {
+	return l.RetainIf(other.Contains)
+}
+
+func (l *LinkedHashSet[T]) DifferenceWith(other Set[T]) int {
+	return differenceWith(l, other)
+}
+
+// SymmetricDifferenceWith deletes the elements of this set that are in the
+// other set and adds those that are not to the end of this set
+// (**X** ← **X** Δ **Y**). It returns the number of elements added or deleted.
+func (l *LinkedHashSet[T]) SymmetricDifferenceWith(other Set[T]) int {
+	return symmetricDifferenceWith(l, other)
+}
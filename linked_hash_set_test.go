@@ -0,0 +1,139 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func newLinkedHashSet[T comparable](elems ...T) *LinkedHashSet[T] {
+	l := NewLinkedHashSet[T]()
+	for _, elem := range elems {
+		l.Insert(elem)
+	}
+	return l
+}
+
+func TestLinkedHashSetOrder(t *testing.T) {
+	var l LinkedHashSet[string] // the zero value is ready to use
+	if l.Contains("x") || l.MoveToFront("x") || !l.IsEmpty() || l.String() != "{}" {
+		t.Error("zero LinkedHashSet should behave as an empty set")
+	}
+
+	for _, w := range []string{"c", "a", "b", "a"} {
+		l.Insert(w)
+	}
+
+	if got, want := l.ToSlice(), []string{"c", "a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("ToSlice() = %v, want %v", got, want)
+	}
+	if got, want := l.String(), "{c, a, b}"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// The order must be stable between calls.
+	for i := 0; i < 5; i++ {
+		if got := slices.Collect(l.All()); !slices.Equal(got, []string{"c", "a", "b"}) {
+			t.Fatalf("All() = %v on call %d", got, i)
+		}
+	}
+
+	l.Remove("a")
+	l.Insert("a")
+	if got, want := l.ToSlice(), []string{"c", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("after re-inserting, ToSlice() = %v, want %v", got, want)
+	}
+
+	if !l.MoveToFront("a") || !l.MoveToBack("c") || l.MoveToBack("z") {
+		t.Error("MoveToFront/MoveToBack reported the wrong membership")
+	}
+	if got, want := l.ToSlice(), []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("after moving, ToSlice() = %v, want %v", got, want)
+	}
+	if l.Cardinality() != 3 {
+		t.Errorf("Cardinality() = %d, want 3", l.Cardinality())
+	}
+}
+
+func TestLinkedHashSetRemoveDuringIteration(t *testing.T) {
+	l := newLinkedHashSet(1, 2, 3, 4, 5)
+	var visited []int
+	for elem := range l.All() {
+		visited = append(visited, elem)
+		if elem%2 == 1 {
+			l.Remove(elem)
+		}
+	}
+	if want := []int{1, 2, 3, 4, 5}; !slices.Equal(visited, want) {
+		t.Errorf("visited %v, want %v", visited, want)
+	}
+	if got, want := l.ToSlice(), []int{2, 4}; !slices.Equal(got, want) {
+		t.Errorf("ToSlice() = %v, want %v", got, want)
+	}
+}
+
+func TestLinkedHashSetOperations(t *testing.T) {
+	a := newLinkedHashSet(4, 1, 3, 2)
+	b := newLinkedHashSet(5, 3, 4)
+
+	tests := []struct {
+		name     string
+		result   Set[int]
+		expected []int
+	}{
+		{"Union", a.Union(b), []int{4, 1, 3, 2, 5}},
+		{"Intersection", a.Intersection(b), []int{4, 3}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"Reverse Union", b.Union(a), []int{5, 3, 4, 1, 2}},
+		{"Union with mockSet", a.Union(newMockSet(9, 8)), []int{4, 1, 3, 2, 9, 8}},
+		{"Intersection with mockSet", a.Intersection(newMockSet(2, 4)), []int{4, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, ok := tt.result.(*LinkedHashSet[int])
+			if !ok {
+				t.Fatalf("%s returned %T, want *LinkedHashSet[int]", tt.name, tt.result)
+			}
+			if got := l.ToSlice(); !slices.Equal(got, tt.expected) {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+
+	if !a.Equals(newLinkedHashSet(1, 2, 3, 4)) || !a.Equals(newMockSet(2, 3, 4, 1)) || a.Equals(b) {
+		t.Error("Equals should ignore order")
+	}
+	if !a.Intersection(b).IsProperSubsetOf(a) || !a.IsProperSupersetOf(newMockSet(1)) || a.IsSubsetOf(b) || !b.IsSupersetOf(newMockSet(5)) {
+		t.Error("subset relations are incorrect")
+	}
+}
+
+func TestLinkedHashSetBulkOperations(t *testing.T) {
+	l := newLinkedHashSet("c", "a")
+	if added := l.InsertAll("b", "a", "d"); added != 2 {
+		t.Errorf("InsertAll() = %d, want 2", added)
+	}
+	if added := l.UnionWith(newMockSet("f", "e", "c")); added != 2 {
+		t.Errorf("UnionWith() = %d, want 2", added)
+	}
+	if got := l.ToSlice(); !slices.Equal(got, []string{"c", "a", "b", "d", "f", "e"}) {
+		t.Errorf("after InsertAll() and UnionWith() order = %v", got)
+	}
+
+	if elem, ok := l.Pop(); !ok || elem != "c" {
+		t.Errorf("Pop() = %q, %v, want the oldest element", elem, ok)
+	}
+	if removed := l.RemoveIf(func(elem string) bool { return elem < "c" }); removed != 2 {
+		t.Errorf("RemoveIf() = %d, want 2", removed)
+	}
+	if got := l.ToSlice(); !slices.Equal(got, []string{"d", "f", "e"}) {
+		t.Errorf("after RemoveIf() order = %v", got)
+	}
+
+	l.Clear()
+	l.Insert("z")
+	if got := l.ToSlice(); !slices.Equal(got, []string{"z"}) {
+		t.Errorf("Insert() after Clear() = %v, want [z]", got)
+	}
+}
@@ -0,0 +1,420 @@
+package set
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Set literals are the format produced by the String methods of the sets and
+// tuples of this package:
+//
+//	set     = "{" [ element { "," element } ] "}" | "∅"
+//	element = set | tuple | quoted | bare
+//	tuple   = "(" element "," element [ "," element ] ")"
+//
+// A quoted element is a Go double-quoted string literal, with the escapes of
+// strconv.Unquote. A bare element is any other run of characters up to the
+// next delimiter, without its surrounding white space. String quotes the
+// string elements that would not read back as bare elements.
+
+// SyntaxError describes where and why a set literal could not be parsed.
+type SyntaxError struct {
+	// Offset is the byte offset in the input at which the error occurred.
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("set: syntax error at offset %d: %s", e.Offset, e.Msg)
+}
+
+// Parse returns a new set containing the elements of a set literal such as
+// "{1, 2, 3}" or "∅". It is the inverse of String for concrete element types:
+// for every set s whose element type is one of those listed below, other than
+// an interface type, Parse(s.String()) equals s.
+//
+// The supported element types are those whose underlying type is a string,
+// integer, floating-point or boolean type, FrozenSet of a supported type for
+// nested sets, and Pair, Pair2 and Triple of supported types. Elements of an
+// interface type, such as any, are inferred from the literal: nested sets
+// become FrozenSet[any], tuples Pair[any] or Triple[any, any, any], quoted
+// strings strings, and bare elements bools, ints, float64s or strings,
+// whichever parses first. Since String does not quote such strings, they do
+// not round-trip: the string "1" in a Set[any] is read back as the int 1.
+// Repeated elements are stored once.
+//
+// Malformed input results in a *SyntaxError giving the position of the error.
+func Parse[T comparable](literal string) (Set[T], error) {
+	s := NewHashSet[T]()
+	if err := ParseInto(literal, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ParseInto is like Parse, but replaces the contents of s instead of creating
+// a new set. On error, s is left unchanged. Elements too large for a BitSet
+// result in an error wrapping ErrTooLarge.
+func ParseInto[T comparable](literal string, s Set[T]) error {
+	p := &literalParser{input: literal}
+	elems, err := parseSetLiteral[T](p)
+	if err != nil {
+		return err
+	}
+	if p.skipSpace(); p.pos < len(p.input) {
+		return p.errorf("unexpected %q after the set", p.peek())
+	}
+	if err := checkDecodedElements(s, elems, len(literal)); err != nil {
+		return err
+	}
+	replaceElements(s, elems)
+	return nil
+}
+
+// MustParse is like Parse but panics if the literal cannot be parsed. It
+// simplifies writing sets in tests and in the initialization of globals.
+func MustParse[T comparable](literal string) Set[T] {
+	s, err := Parse[T](literal)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// literalParser holds the state of parsing a set literal.
+type literalParser struct {
+	input string
+	pos   int
+}
+
+// literalElement is implemented by pointers to the composite element types
+// that know how to parse themselves from a literal.
+type literalElement interface {
+	parseLiteral(p *literalParser) error
+}
+
+func (p *literalParser) errorf(format string, args ...any) error {
+	return &SyntaxError{Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *literalParser) skipSpace() {
+	for p.pos < len(p.input) {
+		r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// peek returns the next rune of the input, or 0 at its end.
+func (p *literalParser) peek() rune {
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+	return r
+}
+
+// expect skips white space and the rune r, or fails if another rune follows.
+func (p *literalParser) expect(r rune) error {
+	p.skipSpace()
+	if next := p.peek(); next != r {
+		if next == 0 {
+			return p.errorf("expected %q, found end of input", r)
+		}
+		return p.errorf("expected %q, found %q", r, next)
+	}
+	p.pos += utf8.RuneLen(r)
+	return nil
+}
+
+// parseSetLiteral parses a set literal and returns its elements.
+func parseSetLiteral[T comparable](p *literalParser) ([]T, error) {
+	p.skipSpace()
+	if p.peek() == '∅' {
+		p.pos += len("∅")
+		return []T{}, nil
+	}
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	elems := []T{}
+	if p.skipSpace(); p.peek() == '}' {
+		p.pos++
+		return elems, nil
+	}
+	for {
+		elem, err := parseElement[T](p)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return elems, nil
+		case 0:
+			return nil, p.errorf("unterminated set, expected ',' or '}'")
+		default:
+			return nil, p.errorf("expected ',' or '}', found %q", p.peek())
+		}
+	}
+}
+
+// parseElement parses a single element of type T.
+func parseElement[T comparable](p *literalParser) (T, error) {
+	var elem T
+	if e, ok := any(&elem).(literalElement); ok {
+		return elem, e.parseLiteral(p)
+	}
+
+	v := reflect.ValueOf(&elem).Elem()
+	if v.Kind() == reflect.Interface {
+		start := p.pos
+		x, err := parseAnyElement(p)
+		if err != nil {
+			return elem, err
+		}
+		if !reflect.TypeOf(x).AssignableTo(v.Type()) {
+			p.pos = start
+			return elem, p.errorf("%T element is not a %v", x, v.Type())
+		}
+		v.Set(reflect.ValueOf(x))
+		return elem, nil
+	}
+
+	p.skipSpace()
+	start := p.pos
+	text, quoted, err := p.scalar()
+	if err != nil {
+		return elem, err
+	}
+	if quoted && v.Kind() != reflect.String {
+		p.pos = start
+		return elem, p.errorf("quoted string is not a %v", v.Type())
+	}
+	if err := parseScalar(v, text); err != nil {
+		p.pos = start
+		return elem, p.errorf("%v", err)
+	}
+	return elem, nil
+}
+
+// parseAnyElement parses an element whose type is inferred from the literal.
+func parseAnyElement(p *literalParser) (any, error) {
+	p.skipSpace()
+	switch p.peek() {
+	case '{', '∅':
+		var f FrozenSet[any]
+		err := f.parseLiteral(p)
+		return f, err
+	case '(':
+		return parseAnyTuple(p)
+	}
+
+	text, quoted, err := p.scalar()
+	if err != nil || quoted {
+		return text, err
+	}
+	if text == "true" || text == "false" {
+		return text == "true", nil
+	}
+	if i, err := strconv.Atoi(text); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil {
+		return f, nil
+	}
+	return text, nil
+}
+
+// parseAnyTuple parses a Pair[any] or a Triple[any, any, any].
+func parseAnyTuple(p *literalParser) (any, error) {
+	start := p.pos
+	var t Triple[any, any, any]
+	n, err := parseTuple(p, &t.First, &t.Second, &t.Third)
+	switch {
+	case err != nil:
+		return nil, err
+	case n == 2:
+		return Pair[any]{First: t.First, Second: t.Second}, nil
+	case n == 3:
+		return t, nil
+	}
+	p.pos = start
+	return nil, p.errorf("tuple of %d elements, want 2 or 3", n)
+}
+
+// parseTuple parses a tuple of up to len(elems) elements of any type into
+// elems and returns the number of elements read.
+func parseTuple(p *literalParser, elems ...*any) (int, error) {
+	if err := p.expect('('); err != nil {
+		return 0, err
+	}
+	for i, elem := range elems {
+		x, err := parseAnyElement(p)
+		if err != nil {
+			return 0, err
+		}
+		*elem = x
+		p.skipSpace()
+		if p.peek() == ')' {
+			p.pos++
+			return i + 1, nil
+		}
+		if err := p.expect(','); err != nil {
+			return 0, err
+		}
+	}
+	return 0, p.errorf("tuple has more than %d elements", len(elems))
+}
+
+// scalar reads a quoted or bare element and returns its text.
+func (p *literalParser) scalar() (text string, quoted bool, err error) {
+	p.skipSpace()
+	rest := p.input[p.pos:]
+	if strings.HasPrefix(rest, `"`) {
+		prefix, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return "", false, p.errorf("invalid quoted string")
+		}
+		text, _ = strconv.Unquote(prefix)
+		p.pos += len(prefix)
+		return text, true, nil
+	}
+
+	end := strings.IndexAny(rest, `{}(),"`)
+	if end < 0 {
+		end = len(rest)
+	}
+	text = strings.TrimRightFunc(rest[:end], unicode.IsSpace)
+	if text == "" {
+		if end == len(rest) {
+			return "", false, p.errorf("expected element, found end of input")
+		}
+		return "", false, p.errorf("expected element, found %q", rest[end])
+	}
+	p.pos += len(text)
+	return text, false, nil
+}
+
+// parseScalar sets v, whose underlying type is a string, integer,
+// floating-point or boolean type, to the value written as text.
+func parseScalar(v reflect.Value, text string) error {
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var x int64
+		if x, err = strconv.ParseInt(text, 10, v.Type().Bits()); err == nil {
+			v.SetInt(x)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var x uint64
+		if x, err = strconv.ParseUint(text, 10, v.Type().Bits()); err == nil {
+			v.SetUint(x)
+		}
+	case reflect.Float32, reflect.Float64:
+		var x float64
+		if x, err = strconv.ParseFloat(text, v.Type().Bits()); err == nil {
+			v.SetFloat(x)
+		}
+	case reflect.Bool:
+		var x bool
+		if x, err = strconv.ParseBool(text); err == nil {
+			v.SetBool(x)
+		}
+	default:
+		return fmt.Errorf("%v elements cannot be parsed", v.Type())
+	}
+	if err != nil {
+		return fmt.Errorf("%q is not a valid %v", text, v.Type())
+	}
+	return nil
+}
+
+func (f *FrozenSet[T]) parseLiteral(p *literalParser) error {
+	elems, err := parseSetLiteral[T](p)
+	if err != nil {
+		return err
+	}
+	*f = NewFrozenSet(elems...)
+	return nil
+}
+
+func (pair *Pair[T]) parseLiteral(p *literalParser) error {
+	return (*Pair2[T, T])(pair).parseLiteral(p)
+}
+
+func (pair *Pair2[A, B]) parseLiteral(p *literalParser) error {
+	var err error
+	if err = p.expect('('); err != nil {
+		return err
+	}
+	if pair.First, err = parseElement[A](p); err != nil {
+		return err
+	}
+	if err = p.expect(','); err != nil {
+		return err
+	}
+	if pair.Second, err = parseElement[B](p); err != nil {
+		return err
+	}
+	return p.expect(')')
+}
+
+func (t *Triple[A, B, C]) parseLiteral(p *literalParser) error {
+	var err error
+	if err = p.expect('('); err != nil {
+		return err
+	}
+	if t.First, err = parseElement[A](p); err != nil {
+		return err
+	}
+	if err = p.expect(','); err != nil {
+		return err
+	}
+	if t.Second, err = parseElement[B](p); err != nil {
+		return err
+	}
+	if err = p.expect(','); err != nil {
+		return err
+	}
+	if t.Third, err = parseElement[C](p); err != nil {
+		return err
+	}
+	return p.expect(')')
+}
+
+// formatElement returns the text of elem in a set literal. Strings that would
+// not read back as bare elements are quoted; other values are formatted with
+// the %v verb.
+func formatElement(elem any) string {
+	if _, ok := elem.(fmt.Stringer); !ok {
+		if v := reflect.ValueOf(elem); v.Kind() == reflect.String {
+			return quoteIfNeeded(v.String())
+		}
+	}
+	return fmt.Sprintf("%v", elem)
+}
+
+// quoteIfNeeded returns s, or s as a quoted string if it is empty, has
+// surrounding white space, or contains a delimiter, a quote, the empty set
+// sign or a non-printable character.
+func quoteIfNeeded(s string) string {
+	if s == "" || strings.ContainsAny(s, `{}(),"∅`) || strings.TrimSpace(s) != s ||
+		strings.IndexFunc(s, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,188 @@
+package set
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		literal  string
+		expected Set[string]
+	}{
+		{"bare words", "{b, a, c}", newMockSet("a", "b", "c")},
+		{"white space", " {  a b ,c\t}\n", newMockSet("a b", "c")},
+		{"quoted", `{"a, b", "\"q\"", "tab\t", ""}`, newMockSet("a, b", `"q"`, "tab\t", "")},
+		{"duplicates", "{a, a}", newMockSet("a")},
+		{"empty", "{}", newMockSet[string]()},
+		{"empty set sign", "∅", newMockSet[string]()},
+		{"unicode", "{日本, ∅x}", newMockSet("日本", "∅x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse[string](tt.literal)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.literal, err)
+			}
+			if !got.Equals(tt.expected) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.literal, got, tt.expected)
+			}
+		})
+	}
+
+	if got := MustParse[float64]("{1.5, -2, 1e3}"); !got.Equals(newMockSet(1.5, -2, 1000)) {
+		t.Errorf("MustParse() of floats = %v", got)
+	}
+	if got := MustParse[bool]("{true, false}"); got.Cardinality() != 2 {
+		t.Errorf("MustParse() of bools = %v", got)
+	}
+	type id uint16
+	if got := MustParse[id]("{7, 65535}"); !got.Equals(newMockSet[id](7, 65535)) {
+		t.Errorf("MustParse() of a named type = %v", got)
+	}
+}
+
+func TestParseNested(t *testing.T) {
+	sets := MustParse[FrozenSet[int]]("{{1, 2}, ∅, {2, 1}, {}}")
+	if !sets.Equals(newMockSet(NewFrozenSet(1, 2), NewFrozenSet[int]())) {
+		t.Errorf("Parse() of nested sets = %v", sets)
+	}
+
+	pairs := MustParse[Pair2[string, int]](`{(a, 1), ("b, c", -2)}`)
+	if !pairs.Equals(newMockSet(Pair2[string, int]{"a", 1}, Pair2[string, int]{"b, c", -2})) {
+		t.Errorf("Parse() of pairs = %v", pairs)
+	}
+
+	same := MustParse[Pair[int]]("{(1, 2), (2, 1)}")
+	if !same.Equals(newMockSet(Pair[int]{1, 2}, Pair[int]{2, 1})) {
+		t.Errorf("Parse() of same-type pairs = %v", same)
+	}
+
+	triples := MustParse[Triple[int, FrozenSet[string], bool]]("{(1, {x, y}, true)}")
+	want := Triple[int, FrozenSet[string], bool]{1, NewFrozenSet("x", "y"), true}
+	if !triples.Equals(newMockSet(want)) {
+		t.Errorf("Parse() of triples = %v", triples)
+	}
+
+	// The element types of an interface type are inferred.
+	mixed := MustParse[any](`{1, 2.5, {3, 4}, (x, "5"), true, word, ∅}`)
+	expected := newMockSet[any](1, 2.5, NewFrozenSet[any](3, 4), Pair[any]{"x", "5"}, true, "word", NewFrozenSet[any]())
+	if !mixed.Equals(expected) {
+		t.Errorf("Parse() of mixed elements = %v, want %v", mixed, expected)
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	strs := NewHashSet[string]()
+	strs.InsertAll("plain", "with space", " padded ", "", "a,b", "{x}", "(y)", `"quoted"`, "back\\slash", "tab\t", "∅", "日本")
+	testParseRoundTrip(t, strs)
+
+	ints := NewSortedSet[int64]()
+	ints.InsertAll(-1<<63, 0, 1<<63-1)
+	testParseRoundTrip[int64](t, ints)
+
+	testParseRoundTrip[float64](t, MustParse[float64]("{0.1, -3, 1e+100, 5e-324}"))
+	testParseRoundTrip[string](t, newLinkedHashSet("z", "a, b", "m"))
+
+	pairs := NewHashSet[Pair2[string, FrozenSet[int]]]()
+	pairs.Insert(Pair2[string, FrozenSet[int]]{"a (b)", NewFrozenSet(1, 2)})
+	pairs.Insert(Pair2[string, FrozenSet[int]]{"", NewFrozenSet[int]()})
+	testParseRoundTrip(t, pairs)
+
+	nested := NewHashSet[FrozenSet[FrozenSet[string]]]()
+	nested.Insert(NewFrozenSet(NewFrozenSet("a", "}"), NewFrozenSet[string]()))
+	testParseRoundTrip(t, nested)
+}
+
+func testParseRoundTrip[T comparable](t *testing.T, s Set[T]) {
+	t.Helper()
+	got, err := Parse[T](s.String())
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", s.String(), err)
+	}
+	if !got.Equals(s) {
+		t.Errorf("Parse(%q) = %v, want %v", s.String(), got, s)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		literal string
+		offset  int
+	}{
+		{"empty input", "", 0},
+		{"missing brace", "1, 2", 0},
+		{"unterminated", "{1, 2", 5},
+		{"missing element", "{1, , 2}", 4},
+		{"trailing comma", "{1,}", 3},
+		{"not a number", "{1, x2}", 4},
+		{"out of range", "{1, 300}", 4},
+		{"quoted number", `{"1"}`, 1},
+		{"bad escape", `{1, "\q"}`, 4},
+		{"trailing input", "{1} 2", 4},
+		{"nested set of ints", "{1, {2}}", 4},
+		{"offset after multibyte", "{∅, x}", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse[int8](tt.literal)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want a *SyntaxError", tt.literal, err)
+			}
+			if syntaxErr.Offset != tt.offset {
+				t.Errorf("Parse(%q) error at offset %d, want %d: %v", tt.literal, syntaxErr.Offset, tt.offset, err)
+			}
+		})
+	}
+
+	if _, err := Parse[Pair[int]]("{(1, 2, 3)}"); err == nil {
+		t.Error("Parse() accepted a triple as a pair")
+	}
+	if _, err := Parse[fmtStringer]("{1}"); err == nil {
+		t.Error("Parse() stored an int in an interface it does not implement")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParse() of an invalid literal did not panic")
+		}
+	}()
+	MustParse[int]("{")
+}
+
+type fmtStringer interface{ String() string }
+
+func TestParseInto(t *testing.T) {
+	s := NewSortedSet[int]()
+	s.Insert(9)
+	if err := ParseInto[int]("{3, 1, 2}", s); err != nil || !slices.Equal(s.ToSlice(), []int{1, 2, 3}) {
+		t.Errorf("ParseInto() = %v, %v, want [1 2 3]", s.ToSlice(), err)
+	}
+	if err := ParseInto[int]("{4, x}", s); err == nil || !slices.Equal(s.ToSlice(), []int{1, 2, 3}) {
+		t.Errorf("failed ParseInto() = %v, %v, want the set unchanged", s.ToSlice(), err)
+	}
+
+	b := NewBitSet()
+	huge := strconv.FormatUint(uint64(MaxBitSetElement)+1, 10)
+	if err := ParseInto[uint]("{1, "+huge+"}", b); !errors.Is(err, ErrTooLarge) || !b.IsEmpty() {
+		t.Errorf("ParseInto() of a huge BitSet element = %v, %v, want ErrTooLarge and the set unchanged", b, err)
+	}
+}
+
+func TestParseInterfaceElements(t *testing.T) {
+	// Elements of interface types are inferred, so strings that look like
+	// other values do not round-trip.
+	s := NewHashSet[any]()
+	s.InsertAll("1", "true", "word")
+	got := MustParse[any](s.String())
+	if want := newMockSet[any](1, true, "word"); !got.Equals(want) {
+		t.Errorf("Parse(%q) = %v, want %v", s.String(), got, want)
+	}
+}
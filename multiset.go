@@ -0,0 +1,276 @@
+package set
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Multiset, or bag, is a collection that, unlike a set, remembers how many
+// times each element was added. The number of copies of an element is its
+// multiplicity; elements whose multiplicity drops to zero are no longer in the
+// multiset.
+//
+// Multiset is not safe for concurrent use. The zero value of a Multiset is an
+// empty multiset ready to use.
+type Multiset[T comparable] struct {
+	counts map[T]int
+
+	// size is the sum of the multiplicities.
+	size int
+}
+
+// Multiplicity is an element of a Multiset together with the number of its
+// copies, as returned by MostCommon.
+type Multiplicity[T comparable] struct {
+	Elem  T
+	Count int
+}
+
+// NewMultiset creates and returns a new empty multiset.
+func NewMultiset[T comparable]() *Multiset[T] {
+	return &Multiset[T]{counts: make(map[T]int)}
+}
+
+// MultisetFromSet returns a new multiset containing each element of s once.
+func MultisetFromSet[T comparable](s Set[T]) *Multiset[T] {
+	m := &Multiset[T]{counts: make(map[T]int, s.Cardinality())}
+	for elem := range s.All() {
+		m.counts[elem] = 1
+	}
+	m.size = len(m.counts)
+	return m
+}
+
+func (m *Multiset[T]) lazyInit() {
+	if m.counts == nil {
+		m.counts = make(map[T]int)
+	}
+}
+
+// Add adds n copies of elem and returns its new multiplicity. It panics if n
+// is negative.
+func (m *Multiset[T]) Add(elem T, n int) int {
+	if n < 0 {
+		panic("set: negative multiplicity " + strconv.Itoa(n))
+	}
+	if n == 0 {
+		return m.counts[elem]
+	}
+	m.lazyInit()
+	m.counts[elem] += n
+	m.size += n
+	return m.counts[elem]
+}
+
+// AddAll adds one copy of each of elems, so that repeated elements are
+// counted.
+func (m *Multiset[T]) AddAll(elems ...T) {
+	for _, elem := range elems {
+		m.Add(elem, 1)
+	}
+}
+
+// Remove removes up to n copies of elem and returns the number removed, which
+// is less than n if elem had fewer copies. It panics if n is negative.
+func (m *Multiset[T]) Remove(elem T, n int) int {
+	if n < 0 {
+		panic("set: negative multiplicity " + strconv.Itoa(n))
+	}
+	count := m.counts[elem]
+	if n >= count {
+		delete(m.counts, elem)
+		m.size -= count
+		return count
+	}
+	m.counts[elem] = count - n
+	m.size -= n
+	return n
+}
+
+// Count returns the multiplicity of elem, which is zero if it is not in the
+// multiset.
+func (m *Multiset[T]) Count(elem T) int {
+	return m.counts[elem]
+}
+
+// Contains reports whether the multiset has at least one copy of elem.
+func (m *Multiset[T]) Contains(elem T) bool {
+	return m.counts[elem] > 0
+}
+
+// Len returns the total number of elements, counting every copy.
+func (m *Multiset[T]) Len() int {
+	return m.size
+}
+
+// Distinct returns the number of distinct elements, which is the cardinality
+// of the support set.
+func (m *Multiset[T]) Distinct() int {
+	return len(m.counts)
+}
+
+// IsEmpty reports whether the multiset has no elements.
+func (m *Multiset[T]) IsEmpty() bool {
+	return m.size == 0
+}
+
+// Clear removes all elements from the multiset and returns how many there
+// were, counting every copy.
+func (m *Multiset[T]) Clear() int {
+	n := m.size
+	clear(m.counts)
+	m.size = 0
+	return n
+}
+
+// All returns an iterator over the distinct elements of the multiset and
+// their multiplicities, in no particular order.
+func (m *Multiset[T]) All() iter.Seq2[T, int] {
+	return func(yield func(T, int) bool) {
+		for elem, count := range m.counts {
+			if !yield(elem, count) {
+				return
+			}
+		}
+	}
+}
+
+// Support returns a new set of the distinct elements of the multiset.
+func (m *Multiset[T]) Support() Set[T] {
+	result := &hashSet[T]{elements: make(map[T]struct{}, len(m.counts))}
+	for elem := range m.counts {
+		result.elements[elem] = struct{}{}
+	}
+	return result
+}
+
+// Clone returns a copy of the multiset.
+func (m *Multiset[T]) Clone() *Multiset[T] {
+	result := &Multiset[T]{counts: make(map[T]int, len(m.counts)), size: m.size}
+	for elem, count := range m.counts {
+		result.counts[elem] = count
+	}
+	return result
+}
+
+// Equals reports whether both multisets have the same elements with the same
+// multiplicities.
+func (m *Multiset[T]) Equals(other *Multiset[T]) bool {
+	return m.size == other.size && m.IsSubsetOf(other)
+}
+
+// IsSubsetOf reports whether every element of the multiset has at most as
+// many copies in it as in the other multiset.
+func (m *Multiset[T]) IsSubsetOf(other *Multiset[T]) bool {
+	if m.size > other.size {
+		return false
+	}
+	for elem, count := range m.counts {
+		if count > other.counts[elem] {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new multiset in which the multiplicity of each element is
+// the larger of its multiplicities in both multisets.
+func (m *Multiset[T]) Union(other *Multiset[T]) *Multiset[T] {
+	result := m.Clone()
+	for elem, count := range other.counts {
+		if count > result.counts[elem] {
+			result.Add(elem, count-result.counts[elem])
+		}
+	}
+	return result
+}
+
+// Sum returns a new multiset in which the multiplicity of each element is the
+// sum of its multiplicities in both multisets.
+func (m *Multiset[T]) Sum(other *Multiset[T]) *Multiset[T] {
+	result := m.Clone()
+	for elem, count := range other.counts {
+		result.Add(elem, count)
+	}
+	return result
+}
+
+// Intersection returns a new multiset in which the multiplicity of each
+// element is the smaller of its multiplicities in both multisets.
+func (m *Multiset[T]) Intersection(other *Multiset[T]) *Multiset[T] {
+	smaller, larger := m, other
+	if len(larger.counts) < len(smaller.counts) {
+		smaller, larger = larger, smaller
+	}
+	result := NewMultiset[T]()
+	for elem, count := range smaller.counts {
+		if n := min(count, larger.counts[elem]); n > 0 {
+			result.Add(elem, n)
+		}
+	}
+	return result
+}
+
+// Difference returns a new multiset in which the multiplicity of each element
+// is its multiplicity in this multiset minus that in the other, or zero if the
+// other has more copies.
+func (m *Multiset[T]) Difference(other *Multiset[T]) *Multiset[T] {
+	result := NewMultiset[T]()
+	for elem, count := range m.counts {
+		if n := count - other.counts[elem]; n > 0 {
+			result.Add(elem, n)
+		}
+	}
+	return result
+}
+
+// MostCommon returns the k elements with the highest multiplicities, from the
+// most to the least common. Elements with equal multiplicities are in the
+// order in which String lists them. If k is negative or exceeds the number of
+// distinct elements, all elements are returned.
+func (m *Multiset[T]) MostCommon(k int) []Multiplicity[T] {
+	result := m.sorted()
+	slices.SortStableFunc(result, func(a, b Multiplicity[T]) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	if k >= 0 && k < len(result) {
+		result = result[:k]
+	}
+	return result
+}
+
+// sorted returns the elements and their multiplicities in display order.
+func (m *Multiset[T]) sorted() []Multiplicity[T] {
+	elems := make([]T, 0, len(m.counts))
+	for elem := range m.counts {
+		elems = append(elems, elem)
+	}
+	sortForDisplay(elems)
+
+	result := make([]Multiplicity[T], len(elems))
+	for i, elem := range elems {
+		result[i] = Multiplicity[T]{Elem: elem, Count: m.counts[elem]}
+	}
+	return result
+}
+
+// String returns a string representation of the multiset listing each
+// distinct element with its multiplicity, such as {a: 2, b: 1}. The elements
+// are sorted as in the String method of sets.
+func (m *Multiset[T]) String() string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, e := range m.sorted() {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(formatElement(e.Elem))
+		sb.WriteString(": ")
+		sb.WriteString(strconv.Itoa(e.Count))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func multisetOf[T comparable](elems ...T) *Multiset[T] {
+	m := NewMultiset[T]()
+	m.AddAll(elems...)
+	return m
+}
+
+func TestMultiset(t *testing.T) {
+	var m Multiset[string]
+	if !m.IsEmpty() || m.Count("a") != 0 || m.Remove("a", 1) != 0 || m.String() != "{}" {
+		t.Fatalf("zero Multiset = %v, want empty", &m)
+	}
+
+	if got := m.Add("a", 3); got != 3 {
+		t.Errorf("Add(a, 3) = %d, want 3", got)
+	}
+	m.AddAll("b", "a", "c")
+	if m.Count("a") != 4 || m.Len() != 6 || m.Distinct() != 3 {
+		t.Errorf("Count(a), Len(), Distinct() = %d, %d, %d, want 4, 6, 3", m.Count("a"), m.Len(), m.Distinct())
+	}
+	if got, want := m.String(), "{a: 4, b: 1, c: 1}"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if got := m.Remove("a", 2); got != 2 || m.Count("a") != 2 {
+		t.Errorf("Remove(a, 2) = %d leaving %d, want 2 leaving 2", got, m.Count("a"))
+	}
+	if got := m.Remove("b", 5); got != 1 || m.Contains("b") || m.Distinct() != 2 {
+		t.Errorf("Remove(b, 5) = %d, want 1 and b removed", got)
+	}
+	if m.Add("d", 0) != 0 || m.Contains("d") {
+		t.Error("Add(d, 0) inserted d")
+	}
+	if got := m.Clear(); got != 3 || !m.IsEmpty() || m.Distinct() != 0 {
+		t.Errorf("Clear() = %d, want 3 and an empty multiset", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Add() with a negative count did not panic")
+		}
+	}()
+	m.Add("a", -1)
+}
+
+func TestMultisetAlgebra(t *testing.T) {
+	a := multisetOf("x", "x", "x", "y", "z")
+	b := multisetOf("x", "y", "y", "w")
+
+	tests := []struct {
+		name     string
+		got      *Multiset[string]
+		expected *Multiset[string]
+	}{
+		{"union", a.Union(b), multisetOf("x", "x", "x", "y", "y", "z", "w")},
+		{"sum", a.Sum(b), multisetOf("x", "x", "x", "x", "y", "y", "y", "z", "w")},
+		{"intersection", a.Intersection(b), multisetOf("x", "y")},
+		{"difference", a.Difference(b), multisetOf("x", "x", "z")},
+		{"reverse difference", b.Difference(a), multisetOf("y", "w")},
+		{"empty intersection", a.Intersection(NewMultiset[string]()), NewMultiset[string]()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equals(tt.expected) {
+				t.Errorf("got %v, want %v", tt.got, tt.expected)
+			}
+			if got := tt.got.Len(); got != tt.expected.Len() {
+				t.Errorf("Len() = %d, want %d", got, tt.expected.Len())
+			}
+		})
+	}
+
+	if a.Count("x") != 3 || b.Count("y") != 2 {
+		t.Error("algebra modified its operands")
+	}
+	if !multisetOf("x", "y").IsSubsetOf(a) || multisetOf("y", "y").IsSubsetOf(a) {
+		t.Error("IsSubsetOf() does not compare multiplicities")
+	}
+}
+
+func TestMultisetSupport(t *testing.T) {
+	s := NewSortedSet[int]()
+	s.InsertAll(3, 1, 2)
+	m := MultisetFromSet[int](s)
+	if m.Len() != 3 || m.Count(2) != 1 {
+		t.Errorf("MultisetFromSet() = %v", m)
+	}
+
+	m.Add(2, 4)
+	if support := m.Support(); !support.Equals(s) {
+		t.Errorf("Support() = %v, want %v", support, s)
+	}
+
+	counts := map[int]int{}
+	for elem, count := range m.All() {
+		counts[elem] = count
+	}
+	if counts[2] != 5 || len(counts) != 3 {
+		t.Errorf("All() = %v", counts)
+	}
+}
+
+func TestMostCommon(t *testing.T) {
+	m := multisetOf("b", "a", "c", "c", "c", "b", "a", "d")
+
+	tests := []struct {
+		k        int
+		expected []Multiplicity[string]
+	}{
+		{0, []Multiplicity[string]{}},
+		{1, []Multiplicity[string]{{"c", 3}}},
+		{3, []Multiplicity[string]{{"c", 3}, {"a", 2}, {"b", 2}}},
+		{-1, []Multiplicity[string]{{"c", 3}, {"a", 2}, {"b", 2}, {"d", 1}}},
+		{10, []Multiplicity[string]{{"c", 3}, {"a", 2}, {"b", 2}, {"d", 1}}},
+	}
+
+	for _, tt := range tests {
+		if got := m.MostCommon(tt.k); !slices.Equal(got, tt.expected) {
+			t.Errorf("MostCommon(%d) = %v, want %v", tt.k, got, tt.expected)
+		}
+	}
+}
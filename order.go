@@ -0,0 +1,171 @@
+package set
+
+import (
+	"cmp"
+	"reflect"
+	"strings"
+)
+
+// orderedCompare returns a function comparing values of type T in their
+// natural order if T satisfies cmp.Ordered, that is if its underlying type is
+// an integer, floating-point or string type. Otherwise it returns nil.
+//
+// It lets code that only knows T to be comparable, such as the encoders of
+// hashSet, produce the same deterministic order as SortedSet. The predeclared
+// types are compared directly; other types with such an underlying type go
+// through reflection.
+func orderedCompare[T comparable]() func(a, b T) int {
+	if compare := predeclaredCompare[T](); compare != nil {
+		return compare
+	}
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.String:
+		return func(a, b T) int {
+			return strings.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}
+	}
+	return nil
+}
+
+// predeclaredCompare returns cmp.Compare for T if T is one of the predeclared
+// ordered types, and nil otherwise. Unlike reflection, it does not allocate
+// on each comparison.
+func predeclaredCompare[T comparable]() func(a, b T) int {
+	var compare any
+	switch any(*new(T)).(type) {
+	case string:
+		compare = strings.Compare
+	case int:
+		compare = cmp.Compare[int]
+	case int8:
+		compare = cmp.Compare[int8]
+	case int16:
+		compare = cmp.Compare[int16]
+	case int32:
+		compare = cmp.Compare[int32]
+	case int64:
+		compare = cmp.Compare[int64]
+	case uint:
+		compare = cmp.Compare[uint]
+	case uint8:
+		compare = cmp.Compare[uint8]
+	case uint16:
+		compare = cmp.Compare[uint16]
+	case uint32:
+		compare = cmp.Compare[uint32]
+	case uint64:
+		compare = cmp.Compare[uint64]
+	case uintptr:
+		compare = cmp.Compare[uintptr]
+	case float32:
+		compare = cmp.Compare[float32]
+	case float64:
+		compare = cmp.Compare[float64]
+	default:
+		return nil
+	}
+	// T is the type of the chosen function's arguments.
+	return compare.(func(a, b T) int)
+}
+
+// valueCompare returns a function comparing values of type T in a total order
+// that is deterministic within a process. Ordered types use their natural
+// order as in orderedCompare and bools put false first; other types are
+// compared with compareValues.
+func valueCompare[T comparable]() func(a, b T) int {
+	if compare := orderedCompare[T](); compare != nil {
+		return compare
+	}
+	if compare, ok := any(compareBools).(func(a, b T) int); ok {
+		return compare
+	}
+	return func(a, b T) int {
+		// Taking the addresses keeps the static type of interface types.
+		return compareValues(reflect.ValueOf(&a).Elem(), reflect.ValueOf(&b).Elem())
+	}
+}
+
+// compareValues compares two values of the same comparable type. Numbers and
+// strings are in their natural order and false precedes true. Arrays and
+// structs are compared element by element or field by field, and interfaces
+// by the name of their dynamic type, then by value. Pointers and channels are
+// compared by address, which is only stable within a process.
+func compareValues(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Array:
+		for i := range a.Len() {
+			if c := compareValues(a.Index(i), b.Index(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if c := compareValues(a.Field(i), b.Field(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Interface:
+		return compareInterfaces(a, b)
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return cmp.Compare(a.Pointer(), b.Pointer())
+	}
+	return compareScalars(a, b)
+}
+
+// compareScalars compares two numbers, strings or booleans of the same type.
+func compareScalars(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.String:
+		return strings.Compare(a.String(), b.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.Complex64, reflect.Complex128:
+		if c := cmp.Compare(real(a.Complex()), real(b.Complex())); c != 0 {
+			return c
+		}
+		return cmp.Compare(imag(a.Complex()), imag(b.Complex()))
+	case reflect.Bool:
+		return compareBools(a.Bool(), b.Bool())
+	}
+	return 0
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// compareInterfaces orders nil before any other value, then values by the
+// name of their dynamic type, then by value.
+func compareInterfaces(a, b reflect.Value) int {
+	switch {
+	case a.IsNil() || b.IsNil():
+		return compareBools(!a.IsNil(), !b.IsNil())
+	case a.Elem().Type() != b.Elem().Type():
+		return strings.Compare(a.Elem().Type().String(), b.Elem().Type().String())
+	}
+	return compareValues(a.Elem(), b.Elem())
+}
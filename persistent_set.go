@@ -0,0 +1,450 @@
+package set
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// Each level of the hash array mapped trie consumes hamtBits bits of an
+// element's 64-bit hash, giving nodes with up to 32 children.
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// persistentSeed is shared by all persistent sets, so that tries built from
+// different sets agree on where every element lives.
+var persistentSeed = maphash.MakeSeed()
+
+func persistentHash[T comparable](elem T) uint64 {
+	return maphash.Comparable(persistentSeed, elem)
+}
+
+// hamtEntry is either a leaf holding a single element or a link to a child node.
+type hamtEntry[T comparable] struct {
+	hash  uint64
+	value T
+	child *hamtNode[T]
+}
+
+// hamtNode is a node of a hash array mapped trie. The bitmap records which of
+// the 32 possible slots are occupied, and entries holds only the occupied
+// slots in order, so sparse nodes stay small.
+//
+// Once every bit of the hash has been consumed, elements whose hashes collide
+// completely are kept in the collisions slice of a leaf node instead.
+//
+// Nodes are never modified after construction; updates copy the path from the
+// root to the changed node and share every other node with the old trie.
+type hamtNode[T comparable] struct {
+	bitmap     uint32
+	entries    []hamtEntry[T]
+	collisions []T
+}
+
+// slot returns the bit of the slot for hash at the given shift and the index
+// of that slot in entries.
+func (n *hamtNode[T]) slot(hash uint64, shift uint) (bit uint32, index int) {
+	bit = 1 << ((hash >> shift) & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// withEntry returns a copy of n with the entry at index replaced.
+func (n *hamtNode[T]) withEntry(index int, e hamtEntry[T]) *hamtNode[T] {
+	entries := slices.Clone(n.entries)
+	entries[index] = e
+	return &hamtNode[T]{bitmap: n.bitmap, entries: entries}
+}
+
+func (n *hamtNode[T]) contains(hash uint64, elem T, shift uint) bool {
+	for n != nil {
+		if shift >= 64 {
+			return slices.Contains(n.collisions, elem)
+		}
+		bit, index := n.slot(hash, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		e := n.entries[index]
+		if e.child == nil {
+			return e.hash == hash && e.value == elem
+		}
+		n, shift = e.child, shift+hamtBits
+	}
+	return false
+}
+
+// insert returns a trie that also contains elem and whether elem was added.
+// If elem is already present, n itself is returned.
+func (n *hamtNode[T]) insert(hash uint64, elem T, shift uint) (*hamtNode[T], bool) {
+	if n == nil {
+		n = &hamtNode[T]{}
+	}
+	if shift >= 64 {
+		if slices.Contains(n.collisions, elem) {
+			return n, false
+		}
+		return &hamtNode[T]{collisions: append(slices.Clone(n.collisions), elem)}, true
+	}
+
+	bit, index := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return &hamtNode[T]{
+			bitmap:  n.bitmap | bit,
+			entries: slices.Insert(slices.Clone(n.entries), index, hamtEntry[T]{hash: hash, value: elem}),
+		}, true
+	}
+
+	e := n.entries[index]
+	if e.child != nil {
+		child, added := e.child.insert(hash, elem, shift+hamtBits)
+		if !added {
+			return n, false
+		}
+		return n.withEntry(index, hamtEntry[T]{child: child}), true
+	}
+	if e.hash == hash && e.value == elem {
+		return n, false
+	}
+
+	// The slot holds a different element: push both down into a new child.
+	child, _ := (*hamtNode[T])(nil).insert(e.hash, e.value, shift+hamtBits)
+	child, _ = child.insert(hash, elem, shift+hamtBits)
+	return n.withEntry(index, hamtEntry[T]{child: child}), true
+}
+
+// remove returns a trie without elem and whether elem was removed. It returns
+// nil for an empty trie. If elem is not present, n itself is returned.
+func (n *hamtNode[T]) remove(hash uint64, elem T, shift uint) (*hamtNode[T], bool) {
+	if n == nil {
+		return nil, false
+	}
+	if shift >= 64 {
+		i := slices.Index(n.collisions, elem)
+		if i < 0 {
+			return n, false
+		}
+		if len(n.collisions) == 1 {
+			return nil, true
+		}
+		return &hamtNode[T]{collisions: slices.Delete(slices.Clone(n.collisions), i, i+1)}, true
+	}
+
+	bit, index := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	e := n.entries[index]
+	if e.child == nil {
+		if e.hash != hash || e.value != elem {
+			return n, false
+		}
+		if len(n.entries) == 1 {
+			return nil, true
+		}
+		return &hamtNode[T]{
+			bitmap:  n.bitmap &^ bit,
+			entries: slices.Delete(slices.Clone(n.entries), index, index+1),
+		}, true
+	}
+
+	child, removed := e.child.remove(hash, elem, shift+hamtBits)
+	switch {
+	case !removed:
+		return n, false
+	case child == nil && len(n.entries) == 1:
+		return nil, true
+	case child == nil:
+		return &hamtNode[T]{
+			bitmap:  n.bitmap &^ bit,
+			entries: slices.Delete(slices.Clone(n.entries), index, index+1),
+		}, true
+	}
+
+	// Keep the trie compact: a child left with a single element is replaced by
+	// that element.
+	switch {
+	case len(child.collisions) == 1:
+		return n.withEntry(index, hamtEntry[T]{hash: hash, value: child.collisions[0]}), true
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		return n.withEntry(index, child.entries[0]), true
+	}
+	return n.withEntry(index, hamtEntry[T]{child: child}), true
+}
+
+func (n *hamtNode[T]) iterate(yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	for _, elem := range n.collisions {
+		if !yield(elem) {
+			return false
+		}
+	}
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !e.child.iterate(yield) {
+				return false
+			}
+		} else if !yield(e.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// PersistentSet is an immutable set backed by a hash array mapped trie (HAMT).
+// Updating a PersistentSet with With or Without, or combining it with another
+// set, returns a new version in O(log n) that shares all unchanged parts of
+// the trie with the original. Keeping many historical versions of a set is
+// therefore cheap, and because versions are never modified they can be shared
+// between goroutines without locking.
+//
+// *PersistentSet implements the Set interface. To do so, Insert, Remove and
+// the other mutating methods of the interface, such as InsertAll or UnionWith,
+// replace the receiver with an updated version, as in `*p = *p.With(elem)`.
+// Versions previously obtained from the receiver, or copied from it by value,
+// are not affected because the trie itself is never modified. These are the
+// only methods that change a PersistentSet, and a PersistentSet must not be
+// updated through them while it is shared between goroutines.
+//
+// The zero value of a PersistentSet is an empty set ready to use.
+type PersistentSet[T comparable] struct {
+	root *hamtNode[T]
+	size int
+}
+
+// NewPersistentSet creates and returns a new empty persistent set.
+func NewPersistentSet[T comparable]() *PersistentSet[T] {
+	return &PersistentSet[T]{}
+}
+
+func (p *PersistentSet[T]) emptyLike() Set[T] {
+	return NewPersistentSet[T]()
+}
+
+// With returns a version of the set that also contains elem.
+func (p *PersistentSet[T]) With(elem T) *PersistentSet[T] {
+	result := *p
+	result.Insert(elem)
+	return &result
+}
+
+// Without returns a version of the set that does not contain elem.
+func (p *PersistentSet[T]) Without(elem T) *PersistentSet[T] {
+	result := *p
+	result.Remove(elem)
+	return &result
+}
+
+// Insert replaces the receiver with a version that also contains elem.
+func (p *PersistentSet[T]) Insert(elem T) {
+	root, added := p.root.insert(persistentHash(elem), elem, 0)
+	if added {
+		p.root, p.size = root, p.size+1
+	}
+}
+
+// Remove replaces the receiver with a version that does not contain elem.
+func (p *PersistentSet[T]) Remove(elem T) {
+	root, removed := p.root.remove(persistentHash(elem), elem, 0)
+	if removed {
+		p.root, p.size = root, p.size-1
+	}
+}
+
+// InsertAll replaces the receiver with a version that also contains elems and
+// returns the number of elements added.
+func (p *PersistentSet[T]) InsertAll(elems ...T) int {
+	return insertAll(p, slices.Values(elems))
+}
+
+// RemoveAll replaces the receiver with a version that does not contain elems
+// and returns the number of elements removed.
+func (p *PersistentSet[T]) RemoveAll(elems ...T) int {
+	return removeAll(p, slices.Values(elems))
+}
+
+// RetainIf replaces the receiver with a version without the elements for
+// which keep returns false and returns the number of elements removed.
+func (p *PersistentSet[T]) RetainIf(keep func(T) bool) int {
+	return retainIf(p, keep)
+}
+
+// RemoveIf replaces the receiver with a version without the elements for
+// which remove returns true and returns the number of elements removed.
+func (p *PersistentSet[T]) RemoveIf(remove func(T) bool) int {
+	return removeIf(p, remove)
+}
+
+// Clear replaces the receiver with the empty set and returns the number of
+// elements it had.
+func (p *PersistentSet[T]) Clear() int {
+	n := p.size
+	p.root, p.size = nil, 0
+	return n
+}
+
+// Pop replaces the receiver with a version without an arbitrary element and
+// returns that element. The boolean is false if the set is empty.
+func (p *PersistentSet[T]) Pop() (T, bool) {
+	return pop(p)
+}
+
+func (p *PersistentSet[T]) Contains(elem T) bool {
+	return p.root.contains(persistentHash(elem), elem, 0)
+}
+
+func (p *PersistentSet[T]) Cardinality() int {
+	return p.size
+}
+
+func (p *PersistentSet[T]) IsEmpty() bool {
+	return p.size == 0
+}
+
+func (p *PersistentSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		p.root.iterate(yield)
+	}
+}
+
+func (p *PersistentSet[T]) ToSlice() []T {
+	result := make([]T, 0, p.size)
+	for elem := range p.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+func (p *PersistentSet[T]) String() string {
+	return formatLiteral(sortForDisplay(p.ToSlice()), formatElement)
+}
+
+// Format implements fmt.Formatter. With %#v, the expression builds the set
+// with With, as InsertAll would not change the returned version.
+func (p *PersistentSet[T]) Format(f fmt.State, verb rune) {
+	elems := sortForDisplay(p.ToSlice())
+	if verb != 'v' || !f.Flag('#') {
+		formatSet(f, verb, elems, "", "")
+		return
+	}
+	fmt.Fprintf(f, "set.NewPersistentSet[%s]()", typeArg[T]())
+	for _, elem := range elems {
+		fmt.Fprintf(f, ".With(%#v)", elem)
+	}
+}
+
+func (p *PersistentSet[T]) Equals(other Set[T]) bool {
+	// Versions that share their whole trie are trivially equal.
+	if o, ok := other.(*PersistentSet[T]); ok && o.root == p.root {
+		return true
+	}
+	return equal(p, other)
+}
+
+func (p *PersistentSet[T]) IsSubsetOf(other Set[T]) bool {
+	return isSubset(p, other)
+}
+
+func (p *PersistentSet[T]) IsSupersetOf(other Set[T]) bool {
+	return isSubset(other, p)
+}
+
+func (p *PersistentSet[T]) IsProperSubsetOf(other Set[T]) bool {
+	return p.Cardinality() < other.Cardinality() && p.IsSubsetOf(other)
+}
+
+func (p *PersistentSet[T]) IsProperSupersetOf(other Set[T]) bool {
+	return p.Cardinality() > other.Cardinality() && p.IsSupersetOf(other)
+}
+
+// Union returns a new PersistentSet containing all elements that are in either
+// this set or the other set (**X** ∪ **Y**). When the other set is also a
+// PersistentSet, the elements of the smaller set are added to the larger one,
+// so the result shares structure with the larger operand.
+func (p *PersistentSet[T]) Union(other Set[T]) Set[T] {
+	base, extra := p, other
+	if o, ok := other.(*PersistentSet[T]); ok && o.size > p.size {
+		base, extra = o, p
+	}
+	result := *base
+	Insert(&result, extra.All())
+	return &result
+}
+
+// Intersection returns a new PersistentSet containing all elements that are in
+// both this set and the other set (**X** ∩ **Y**). The result shares structure
+// with this set.
+func (p *PersistentSet[T]) Intersection(other Set[T]) Set[T] {
+	result := *p
+	for elem := range p.All() {
+		if !other.Contains(elem) {
+			result.Remove(elem)
+		}
+	}
+	return &result
+}
+
+// Difference returns a new PersistentSet containing all elements that are in
+// this set but not in the other set (**X** \ **Y**). The result shares
+// structure with this set.
+func (p *PersistentSet[T]) Difference(other Set[T]) Set[T] {
+	result := *p
+	if other.Cardinality() < p.size {
+		for elem := range other.All() {
+			result.Remove(elem)
+		}
+		return &result
+	}
+	for elem := range p.All() {
+		if other.Contains(elem) {
+			result.Remove(elem)
+		}
+	}
+	return &result
+}
+
+// SymmetricDifference returns a new PersistentSet containing all elements that
+// are in either this set or the other set, but not in both (**X** Δ **Y**).
+// The result shares structure with this set.
+func (p *PersistentSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	result := *p
+	for elem := range other.All() {
+		if p.Contains(elem) {
+			result.Remove(elem)
+		} else {
+			result.Insert(elem)
+		}
+	}
+	return &result
+}
+
+// UnionWith replaces the receiver with its union with the other set
+// (**X** ← **X** ∪ **Y**) and returns the number of elements added.
+func (p *PersistentSet[T]) UnionWith(other Set[T]) int {
+	return insertAll(p, other.All())
+}
+
+// IntersectWith replaces the receiver with its intersection with the other set
+// (**X** ← **X** ∩ **Y**) and returns the number of elements removed.
+func (p *PersistentSet[T]) IntersectWith(other Set[T]) int {
+	return retainIf(p, other.Contains)
+}
+
+// DifferenceWith replaces the receiver with its difference with the other set
+// (**X** ← **X** \ **Y**) and returns the number of elements removed.
+func (p *PersistentSet[T]) DifferenceWith(other Set[T]) int {
+	return differenceWith(p, other)
+}
+
+// SymmetricDifferenceWith replaces the receiver with its symmetric difference
+// with the other set (**X** ← **X** Δ **Y**) and returns the number of elements
+// added or removed.
+func (p *PersistentSet[T]) SymmetricDifferenceWith(other Set[T]) int {
+	return symmetricDifferenceWith(p, other)
+}
@@ -0,0 +1,233 @@
+package set
+
+import (
+	"math/rand/v2"
+	"sync"
+	"testing"
+)
+
+func TestPersistentSetVersions(t *testing.T) {
+	var empty PersistentSet[string] // the zero value is ready to use
+	v1 := empty.With("a")
+	v2 := v1.With("b")
+	v3 := v2.Without("a")
+	v4 := v3.With("b")
+
+	tests := []struct {
+		name     string
+		version  *PersistentSet[string]
+		expected []string
+	}{
+		{"empty", &empty, nil},
+		{"v1", v1, []string{"a"}},
+		{"v2", v2, []string{"a", "b"}},
+		{"v3", v3, []string{"b"}},
+		{"v4", v4, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.version.Equals(newMockSet(tt.expected...)) {
+				t.Errorf("%s = %v, want %v", tt.name, tt.version, tt.expected)
+			}
+			if tt.version.Cardinality() != len(tt.expected) {
+				t.Errorf("%s has cardinality %d, want %d", tt.name, tt.version.Cardinality(), len(tt.expected))
+			}
+		})
+	}
+
+	// Updating a version in place through the Set interface must not affect
+	// versions derived from it earlier.
+	var s Set[string] = v2
+	s.Insert("c")
+	s.Remove("a")
+	if !v2.Equals(newMockSet("b", "c")) || !v1.Equals(newMockSet("a")) || !v3.Equals(newMockSet("b")) {
+		t.Errorf("in-place update affected other versions: v1=%v v3=%v", v1, v3)
+	}
+	// The same holds for the bulk operations.
+	s = v2.With("x")
+	s.UnionWith(newMockSet("y", "z"))
+	s.RemoveIf(func(elem string) bool { return elem == "b" })
+	s.Clear()
+	if !v2.Equals(newMockSet("b", "c")) || !s.IsEmpty() {
+		t.Errorf("bulk update affected other versions: v2=%v", v2)
+	}
+	if w := v1.With("a"); w == v1 {
+		t.Error("With should return a new version even when unchanged")
+	}
+}
+
+func TestPersistentSetMatchesHashSet(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	p := NewPersistentSet[int]()
+	h := NewHashSet[int]()
+	versions := []*PersistentSet[int]{p}
+	snapshots := []Set[int]{Collect(h.All())}
+
+	for i := 0; i < 5000; i++ {
+		v := r.IntN(2000)
+		if r.IntN(3) == 0 {
+			p = p.Without(v)
+			h.Remove(v)
+		} else {
+			p = p.With(v)
+			h.Insert(v)
+		}
+		if i%500 == 0 {
+			versions = append(versions, p)
+			snapshots = append(snapshots, Collect(h.All()))
+		}
+	}
+
+	if !p.Equals(h) || !h.Equals(p) {
+		t.Fatal("PersistentSet diverged from hashSet")
+	}
+	for i, version := range versions {
+		if !version.Equals(snapshots[i]) {
+			t.Errorf("version %d was modified by later updates", i)
+		}
+	}
+
+	for v := range h.All() {
+		p = p.Without(v)
+	}
+	if !p.IsEmpty() || p.root != nil {
+		t.Error("removing every element should leave an empty trie")
+	}
+}
+
+func TestPersistentSetStructuralSharing(t *testing.T) {
+	p := NewPersistentSet[int]()
+	for i := 0; i < 10000; i++ {
+		p.Insert(i)
+	}
+	q := p.With(-1)
+
+	shared := 0
+	for i, e := range q.root.entries {
+		if e.child != nil && e.child == p.root.entries[i].child {
+			shared++
+		}
+	}
+	// Only the path to the new element is copied; every other subtree of the
+	// root is shared between the two versions.
+	if shared < len(p.root.entries)-1 {
+		t.Errorf("only %d of %d root subtrees are shared", shared, len(p.root.entries))
+	}
+}
+
+func TestHAMTCollisions(t *testing.T) {
+	// Force complete hash collisions by inserting with the same hash.
+	const hash = 0xDEADBEEF
+	var root *hamtNode[string]
+	for _, elem := range []string{"a", "b", "c"} {
+		var added bool
+		root, added = root.insert(hash, elem, 0)
+		if !added {
+			t.Fatalf("insert(%q) reported not added", elem)
+		}
+	}
+	if _, added := root.insert(hash, "b", 0); added {
+		t.Error("inserting a duplicate colliding element reported added")
+	}
+	for _, elem := range []string{"a", "b", "c"} {
+		if !root.contains(hash, elem, 0) {
+			t.Errorf("contains(%q) = false", elem)
+		}
+	}
+	if root.contains(hash, "d", 0) {
+		t.Error("contains(\"d\") = true")
+	}
+
+	root, _ = root.remove(hash, "a", 0)
+	root, _ = root.remove(hash, "c", 0)
+	if !root.contains(hash, "b", 0) || root.contains(hash, "a", 0) {
+		t.Error("remove broke the collision node")
+	}
+	// The last colliding element is pulled back up to the root.
+	if len(root.entries) != 1 || root.entries[0].child != nil {
+		t.Error("trie was not compacted after removing collisions")
+	}
+	if root, removed := root.remove(hash, "b", 0); !removed || root != nil {
+		t.Error("removing the last element should leave an empty trie")
+	}
+}
+
+func TestPersistentSetOperations(t *testing.T) {
+	build := func(elems ...int) *PersistentSet[int] {
+		p := NewPersistentSet[int]()
+		Insert(p, newMockSet(elems...).All())
+		return p
+	}
+	a := build(1, 2, 3, 4)
+	b := build(3, 4, 5)
+
+	tests := []struct {
+		name     string
+		result   Set[int]
+		expected []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Union with larger other", b.Union(a), []int{1, 2, 3, 4, 5}},
+		{"Intersection", a.Intersection(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"Difference with larger other", a.Difference(build(1, 5, 6, 7, 8, 9)), []int{2, 3, 4}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"Union with mockSet", a.Union(newMockSet(9)), []int{1, 2, 3, 4, 9}},
+		{"Union with empty", a.Union(NewPersistentSet[int]()), []int{1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := tt.result.(*PersistentSet[int])
+			if !ok {
+				t.Fatalf("%s returned %T, want *PersistentSet[int]", tt.name, tt.result)
+			}
+			if !p.Equals(newMockSet(tt.expected...)) || p.Cardinality() != len(tt.expected) {
+				t.Errorf("%s = %v, want %v", tt.name, p, tt.expected)
+			}
+			if p == a || p == b {
+				t.Errorf("%s returned one of its operands", tt.name)
+			}
+		})
+	}
+
+	if !a.Equals(build(1, 2, 3, 4)) || !a.Equals(a.With(1)) || a.Equals(b) {
+		t.Error("Equals is incorrect")
+	}
+	if !a.Intersection(b).IsProperSubsetOf(a) || !a.IsProperSupersetOf(newMockSet(1)) || a.IsSubsetOf(b) || !b.IsSupersetOf(newMockSet(5)) {
+		t.Error("subset relations are incorrect")
+	}
+	if got := NewPersistentSet[string]().With("b").With("a").String(); got != "{a, b}" {
+		t.Errorf("String() = %q, want %q", got, "{a, b}")
+	}
+}
+
+func TestPersistentSetConcurrentReaders(t *testing.T) {
+	p := NewPersistentSet[int]()
+	for i := 0; i < 1000; i++ {
+		p.Insert(i)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Every goroutine derives its own versions from the shared one.
+			local := p
+			for i := 0; i < 200; i++ {
+				local = local.With(1000 + w*1000 + i).Without(i)
+				_ = p.Contains(i)
+			}
+			if local.Cardinality() != 1000 {
+				t.Errorf("derived version has %d elements, want 1000", local.Cardinality())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if p.Cardinality() != 1000 || !p.Contains(0) {
+		t.Error("shared version was modified by derived versions")
+	}
+}
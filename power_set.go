@@ -1,28 +1,145 @@
 package set
 
-// PowerSet returns a slice containing all possible subsets of the input set.
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// DefaultPowerSetLimit is the largest number of elements for which PowerSet
+// builds the power set, which then holds 2^20, about a million, subsets.
+const DefaultPowerSetLimit = 20
+
+// PowerSet returns a set containing all possible subsets of the input set.
 // For a set S, it returns P(S) = {T | T ⊆ S}.
 //
+// The subsets are frozen sets, so the power set behaves as a set of sets:
+// membership is decided by content, and PowerSet(S).Contains(Freeze(T)) is
+// true for every subset T of S.
+//
 // For a set with n elements, the power set contains 2^n elements.
 // For example, if S = {1, 2}, then PowerSet(S) = {{}, {1}, {2}, {1, 2}}.
-func PowerSet[T comparable](s Set[T]) Set[Set[T]] {
-	result := NewHashSet[Set[T]]()
-
-	result.Insert(NewHashSet[T]())
-
-	elements := s.ToSlice()
-	for _, elem := range elements {
-		currentSets := result.ToSlice() // Get current subsets
-		for _, subset := range currentSets {
-			newSubset := NewHashSet[T]()
-			subsetElems := subset.ToSlice()
-			for _, e := range subsetElems {
-				newSubset.Insert(e)
-			}
-			newSubset.Insert(elem)
-			result.Insert(newSubset)
+//
+// If S has more than DefaultPowerSetLimit elements, PowerSet returns an error
+// wrapping ErrTooLarge instead. Use PowerSetWithLimit to choose another limit,
+// or PowerSetSeq to visit the subsets one at a time.
+func PowerSet[T comparable](s Set[T]) (Set[FrozenSet[T]], error) {
+	return PowerSetWithLimit(s, DefaultPowerSetLimit)
+}
+
+// PowerSetWithLimit is like PowerSet, but returns an error wrapping ErrTooLarge
+// only if S has more than limit elements.
+func PowerSetWithLimit[T comparable](s Set[T], limit int) (Set[FrozenSet[T]], error) {
+	n := s.Cardinality()
+	if n > limit {
+		return nil, fmt.Errorf("%w: power set of %d elements exceeds the limit of %d", ErrTooLarge, n, limit)
+	}
+
+	result := &hashSet[FrozenSet[T]]{elements: make(map[FrozenSet[T]]struct{}, 1<<min(n, 16))}
+	for subset := range subsets(context.Background(), s) {
+		result.Insert(freezeOwned(subset))
+	}
+	return result, nil
+}
+
+// PowerSetSeq returns an iterator over all subsets of s, without holding more
+// than one of them in memory. Every subset is a new set, which the caller may
+// keep or modify.
+//
+// The subsets are yielded in binary counting order of the elements of s as
+// they are visited when iteration starts: {}, {a}, {b}, {a, b}, {c}, and so on.
+// Iteration stops early once ctx is done, which the caller can detect by
+// checking ctx.Err().
+func PowerSetSeq[T comparable](ctx context.Context, s Set[T]) iter.Seq[Set[T]] {
+	return asSets(subsets(ctx, s))
+}
+
+// PowerSetSeqOfSize is like PowerSetSeq, but only yields the subsets of s that
+// have exactly k elements, of which there are n choose k. The subsets are
+// yielded in lexicographic order of the positions of their elements in s.
+// If k is negative or larger than the cardinality of s, there are none.
+func PowerSetSeqOfSize[T comparable](ctx context.Context, s Set[T], k int) iter.Seq[Set[T]] {
+	return asSets(subsetsOfSize(ctx, s, k))
+}
+
+func asSets[T comparable](seq iter.Seq[*hashSet[T]]) iter.Seq[Set[T]] {
+	return func(yield func(Set[T]) bool) {
+		for s := range seq {
+			if !yield(s) {
+				return
+			}
 		}
 	}
+}
 
-	return result
+func subsets[T comparable](ctx context.Context, s Set[T]) iter.Seq[*hashSet[T]] {
+	return func(yield func(*hashSet[T]) bool) {
+		elems := s.ToSlice()
+
+		// in is a binary counter with one digit per element, so it works for
+		// sets of any size: the subset it selects contains elems[i] if in[i].
+		in := make([]bool, len(elems))
+		for ctx.Err() == nil {
+			subset := &hashSet[T]{elements: make(map[T]struct{})}
+			for i, elem := range elems {
+				if in[i] {
+					subset.Insert(elem)
+				}
+			}
+			if !yield(subset) {
+				return
+			}
+
+			// Increment the counter. Once it wraps around to all false,
+			// every subset has been visited.
+			i := 0
+			for i < len(in) && in[i] {
+				in[i] = false
+				i++
+			}
+			if i == len(in) {
+				return
+			}
+			in[i] = true
+		}
+	}
+}
+
+func subsetsOfSize[T comparable](ctx context.Context, s Set[T], k int) iter.Seq[*hashSet[T]] {
+	return func(yield func(*hashSet[T]) bool) {
+		elems := s.ToSlice()
+		n := len(elems)
+		if k < 0 || k > n {
+			return
+		}
+
+		// positions holds the increasing indices in elems of the current subset.
+		positions := make([]int, k)
+		for i := range positions {
+			positions[i] = i
+		}
+		for ctx.Err() == nil {
+			subset := &hashSet[T]{elements: make(map[T]struct{}, k)}
+			for _, p := range positions {
+				subset.Insert(elems[p])
+			}
+			if !yield(subset) {
+				return
+			}
+
+			// Advance the rightmost position that can still move, and place
+			// the positions after it directly behind it.
+			i := k - 1
+			for i >= 0 && positions[i] == n-k+i {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			positions[i]++
+			for j := i + 1; j < k; j++ {
+				positions[j] = positions[j-1] + 1
+			}
+		}
+	}
 }
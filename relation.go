@@ -0,0 +1,266 @@
+package set
+
+// Relation is a binary relation on a set of elements, its universe: a set of
+// pairs (a, b), read "a is related to b", whose elements belong to the
+// universe. The pairs are a Set[Pair[T]], such as the result of
+// CartesianProduct or a subset of it.
+//
+// The universe matters for the properties and closures that speak of every
+// element, such as IsReflexive and ReflexiveClosure. It always contains the
+// field of the relation, that is the elements of its pairs.
+//
+// A Relation is not safe for concurrent use. Operations returning a Relation
+// return a new one and leave their operands unchanged.
+type Relation[T comparable] struct {
+	universe Set[T]
+	pairs    Set[Pair[T]]
+}
+
+// NewRelation returns a relation on universe with the given pairs. Either
+// argument may be nil: a nil universe is the field of the pairs and nil pairs
+// are the empty relation. Both sets are copied.
+func NewRelation[T comparable](universe Set[T], pairs Set[Pair[T]]) *Relation[T] {
+	r := &Relation[T]{universe: NewHashSet[T](), pairs: NewHashSet[Pair[T]]()}
+	if universe != nil {
+		r.universe.UnionWith(universe)
+	}
+	if pairs != nil {
+		for p := range pairs.All() {
+			r.Add(p.First, p.Second)
+		}
+	}
+	return r
+}
+
+// Add relates a to b, adding both to the universe.
+func (r *Relation[T]) Add(a, b T) {
+	r.universe.InsertAll(a, b)
+	r.pairs.Insert(Pair[T]{First: a, Second: b})
+}
+
+// Related reports whether a is related to b.
+func (r *Relation[T]) Related(a, b T) bool {
+	return r.pairs.Contains(Pair[T]{First: a, Second: b})
+}
+
+// Cardinality returns the number of pairs in the relation.
+func (r *Relation[T]) Cardinality() int {
+	return r.pairs.Cardinality()
+}
+
+// Universe returns a new set of the elements the relation is defined on.
+func (r *Relation[T]) Universe() Set[T] {
+	return r.universe.Union(NewHashSet[T]())
+}
+
+// Pairs returns a new set of the pairs of the relation.
+func (r *Relation[T]) Pairs() Set[Pair[T]] {
+	return r.pairs.Union(NewHashSet[Pair[T]]())
+}
+
+// Equals reports whether both relations have the same universe and pairs.
+func (r *Relation[T]) Equals(other *Relation[T]) bool {
+	return r.pairs.Equals(other.pairs) && r.universe.Equals(other.universe)
+}
+
+// Domain returns the elements related to at least one element:
+// {a | (a, b) ∈ R}.
+func (r *Relation[T]) Domain() Set[T] {
+	return Map(r.pairs, func(p Pair[T]) T { return p.First })
+}
+
+// Range returns the elements to which at least one element is related:
+// {b | (a, b) ∈ R}.
+func (r *Relation[T]) Range() Set[T] {
+	return Map(r.pairs, func(p Pair[T]) T { return p.Second })
+}
+
+// Image returns the elements to which an element of s is related:
+// {b | (a, b) ∈ R, a ∈ s}.
+func (r *Relation[T]) Image(s Set[T]) Set[T] {
+	result := NewHashSet[T]()
+	for p := range r.pairs.All() {
+		if s.Contains(p.First) {
+			result.Insert(p.Second)
+		}
+	}
+	return result
+}
+
+// PreImage returns the elements related to an element of s:
+// {a | (a, b) ∈ R, b ∈ s}.
+func (r *Relation[T]) PreImage(s Set[T]) Set[T] {
+	return r.Inverse().Image(s)
+}
+
+// Inverse returns the relation with every pair reversed:
+// R⁻¹ = {(b, a) | (a, b) ∈ R}.
+func (r *Relation[T]) Inverse() *Relation[T] {
+	result := &Relation[T]{universe: r.Universe(), pairs: NewHashSet[Pair[T]]()}
+	for p := range r.pairs.All() {
+		result.pairs.Insert(Pair[T]{First: p.Second, Second: p.First})
+	}
+	return result
+}
+
+// Compose returns the relation relating a to c whenever r relates a to some b
+// that other relates to c: {(a, c) | (a, b) ∈ r, (b, c) ∈ other}. In the usual
+// notation this is other ∘ r, applying r first. The universe of the result is
+// the union of both universes.
+func (r *Relation[T]) Compose(other *Relation[T]) *Relation[T] {
+	result := &Relation[T]{universe: r.universe.Union(other.universe), pairs: NewHashSet[Pair[T]]()}
+	successors := other.successors()
+	for p := range r.pairs.All() {
+		for _, c := range successors[p.Second] {
+			result.pairs.Insert(Pair[T]{First: p.First, Second: c})
+		}
+	}
+	return result
+}
+
+// successors maps every element of the domain to the elements it is related
+// to.
+func (r *Relation[T]) successors() map[T][]T {
+	result := make(map[T][]T)
+	for p := range r.pairs.All() {
+		result[p.First] = append(result[p.First], p.Second)
+	}
+	return result
+}
+
+// IsReflexive reports whether every element of the universe is related to
+// itself: ∀a (a, a) ∈ R.
+func (r *Relation[T]) IsReflexive() bool {
+	for a := range r.universe.All() {
+		if !r.Related(a, a) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSymmetric reports whether b is related to a whenever a is related to b:
+// (a, b) ∈ R ⇒ (b, a) ∈ R.
+func (r *Relation[T]) IsSymmetric() bool {
+	for p := range r.pairs.All() {
+		if !r.Related(p.Second, p.First) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAntisymmetric reports whether no two distinct elements are related to
+// each other: (a, b) ∈ R ∧ (b, a) ∈ R ⇒ a = b.
+func (r *Relation[T]) IsAntisymmetric() bool {
+	for p := range r.pairs.All() {
+		if p.First != p.Second && r.Related(p.Second, p.First) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsTransitive reports whether a is related to c whenever a is related to b
+// and b to c: (a, b) ∈ R ∧ (b, c) ∈ R ⇒ (a, c) ∈ R.
+func (r *Relation[T]) IsTransitive() bool {
+	successors := r.successors()
+	for p := range r.pairs.All() {
+		for _, c := range successors[p.Second] {
+			if !r.Related(p.First, c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsTotal reports whether every element of the universe is related to at
+// least one element, that is whether the domain is the whole universe. This
+// is the left-totality required of functions.
+func (r *Relation[T]) IsTotal() bool {
+	return r.Domain().Cardinality() == r.universe.Cardinality()
+}
+
+// IsFunctional reports whether every element is related to at most one
+// element: (a, b) ∈ R ∧ (a, c) ∈ R ⇒ b = c. A functional and total relation
+// is a function on its universe.
+func (r *Relation[T]) IsFunctional() bool {
+	return r.Domain().Cardinality() == r.pairs.Cardinality()
+}
+
+// IsInjective reports whether at most one element is related to each
+// element: (a, c) ∈ R ∧ (b, c) ∈ R ⇒ a = b.
+func (r *Relation[T]) IsInjective() bool {
+	return r.Range().Cardinality() == r.pairs.Cardinality()
+}
+
+// ReflexiveClosure returns the smallest reflexive relation containing r, which
+// adds (a, a) for every element a of the universe.
+func (r *Relation[T]) ReflexiveClosure() *Relation[T] {
+	result := &Relation[T]{universe: r.Universe(), pairs: r.Pairs()}
+	for a := range r.universe.All() {
+		result.pairs.Insert(Pair[T]{First: a, Second: a})
+	}
+	return result
+}
+
+// SymmetricClosure returns the smallest symmetric relation containing r,
+// which is r ∪ r⁻¹.
+func (r *Relation[T]) SymmetricClosure() *Relation[T] {
+	result := r.Inverse()
+	result.pairs.UnionWith(r.pairs)
+	return result
+}
+
+// TransitiveClosure returns the smallest transitive relation containing r,
+// computed with Warshall's algorithm in O(n³/64) time for a universe of n
+// elements. A pair with an element that is not equal to itself, such as a
+// floating-point NaN, is kept as it is but does not take part in composition.
+func (r *Relation[T]) TransitiveClosure() *Relation[T] {
+	elems := r.universe.ToSlice()
+	index := make(map[T]uint, len(elems))
+	for i, elem := range elems {
+		index[elem] = uint(i)
+	}
+
+	// reach[i] holds the elements reachable from elems[i]. After step k it
+	// includes the paths through elems[0..k] only.
+	reach := make([]*BitSet, len(elems))
+	for i := range reach {
+		reach[i] = NewBitSet()
+	}
+	// Elements that are not equal to themselves, such as NaN, cannot be looked
+	// up. Their pairs are copied to the result unchanged.
+	var unindexed []Pair[T]
+	for p := range r.pairs.All() {
+		i, okFirst := index[p.First]
+		j, okSecond := index[p.Second]
+		if !okFirst || !okSecond {
+			unindexed = append(unindexed, p)
+			continue
+		}
+		reach[i].Insert(j)
+	}
+	for k := range reach {
+		for i := range reach {
+			if reach[i].Contains(uint(k)) {
+				reach[i].UnionWith(reach[k])
+			}
+		}
+	}
+
+	result := &Relation[T]{universe: r.Universe(), pairs: NewHashSet[Pair[T]]()}
+	for i, row := range reach {
+		for j := range row.All() {
+			result.pairs.Insert(Pair[T]{First: elems[i], Second: elems[j]})
+		}
+	}
+	result.pairs.InsertAll(unindexed...)
+	return result
+}
+
+// String returns the pairs of the relation, such as {(1, 2), (2, 3)}.
+func (r *Relation[T]) String() string {
+	return r.pairs.String()
+}
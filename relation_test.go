@@ -0,0 +1,170 @@
+package set
+
+import (
+	"math"
+	"testing"
+)
+
+func relationOf[T comparable](universe []T, pairs ...Pair[T]) *Relation[T] {
+	r := NewRelation[T](newMockSet(universe...), nil)
+	for _, p := range pairs {
+		r.Add(p.First, p.Second)
+	}
+	return r
+}
+
+func pairsOf[T comparable](pairs ...[2]T) []Pair[T] {
+	result := make([]Pair[T], len(pairs))
+	for i, p := range pairs {
+		result[i] = Pair[T]{First: p[0], Second: p[1]}
+	}
+	return result
+}
+
+func TestRelationProperties(t *testing.T) {
+	divisors := []int{1, 2, 3, 4, 6, 12}
+	divides := NewRelation[int](nil, Filter(CartesianProduct[int](newMockSet(divisors...), newMockSet(divisors...)), func(p Pair[int]) bool {
+		return p.Second%p.First == 0
+	}))
+	residues := []int{0, 1, 2, 3, 4, 5}
+	congruent := NewRelation[int](nil, Filter(CartesianProduct[int](newMockSet(residues...), newMockSet(residues...)), func(p Pair[int]) bool {
+		return p.First%3 == p.Second%3
+	}))
+	square := NewRelation[int](nil, Map(newMockSet(-2, -1, 0, 1, 2), func(x int) Pair[int] { return Pair[int]{x, x * x} }))
+	// Rosen, Discrete Mathematics and Its Applications, section 9.1.
+	rosen := relationOf([]int{1, 2, 3, 4}, pairsOf([2]int{1, 1}, [2]int{1, 2}, [2]int{2, 1}, [2]int{2, 2}, [2]int{3, 4}, [2]int{4, 1}, [2]int{4, 4})...)
+	successor := relationOf([]int{0, 1, 2, 3}, pairsOf([2]int{0, 1}, [2]int{1, 2}, [2]int{2, 3})...)
+
+	tests := []struct {
+		name                                            string
+		r                                               *Relation[int]
+		reflexive, symmetric, antisymmetric, transitive bool
+		total, functional, injective                    bool
+	}{
+		{"divides", divides, true, false, true, true, true, false, false},
+		{"congruence modulo 3", congruent, true, true, false, true, true, false, false},
+		{"square", square, false, false, true, true, false, true, false},
+		{"rosen", rosen, false, false, false, false, true, false, false},
+		{"successor", successor, false, false, true, false, false, true, true},
+		{"empty", NewRelation[int](nil, nil), true, true, true, true, true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := [7]bool{tt.r.IsReflexive(), tt.r.IsSymmetric(), tt.r.IsAntisymmetric(), tt.r.IsTransitive(),
+				tt.r.IsTotal(), tt.r.IsFunctional(), tt.r.IsInjective()}
+			want := [7]bool{tt.reflexive, tt.symmetric, tt.antisymmetric, tt.transitive, tt.total, tt.functional, tt.injective}
+			if got != want {
+				t.Errorf("reflexive, symmetric, antisymmetric, transitive, total, functional, injective = %v, want %v", got, want)
+			}
+		})
+	}
+
+	// The square relation is a function on its domain, though not on its
+	// universe, which also contains 4.
+	if !square.Domain().Equals(newMockSet(-2, -1, 0, 1, 2)) || !square.Range().Equals(newMockSet(0, 1, 4)) {
+		t.Errorf("Domain(), Range() = %v, %v", square.Domain(), square.Range())
+	}
+}
+
+func TestRelationOperations(t *testing.T) {
+	// Rosen, section 9.1: the composite of R from {1, 2, 3} to {1, 2, 3, 4}
+	// and S from {1, 2, 3, 4} to {0, 1, 2}.
+	r := relationOf(nil, pairsOf([2]int{1, 1}, [2]int{1, 4}, [2]int{2, 3}, [2]int{3, 1}, [2]int{3, 4})...)
+	s := relationOf(nil, pairsOf([2]int{1, 0}, [2]int{2, 0}, [2]int{3, 1}, [2]int{3, 2}, [2]int{4, 1})...)
+	composite := r.Compose(s)
+	want := newMockSet(pairsOf([2]int{1, 0}, [2]int{1, 1}, [2]int{2, 1}, [2]int{2, 2}, [2]int{3, 0}, [2]int{3, 1})...)
+	if !composite.Pairs().Equals(want) {
+		t.Errorf("Compose() = %v, want %v", composite, want)
+	}
+	if !composite.Universe().Equals(newMockSet(0, 1, 2, 3, 4)) {
+		t.Errorf("Compose().Universe() = %v", composite.Universe())
+	}
+
+	inverse := r.Inverse()
+	if !inverse.Related(4, 1) || inverse.Related(1, 4) || inverse.Cardinality() != r.Cardinality() {
+		t.Errorf("Inverse() = %v", inverse)
+	}
+	if !inverse.Inverse().Equals(r) {
+		t.Error("the inverse of the inverse is not the relation")
+	}
+
+	if got := r.Image(newMockSet(1, 2)); !got.Equals(newMockSet(1, 3, 4)) {
+		t.Errorf("Image({1, 2}) = %v, want {1, 3, 4}", got)
+	}
+	if got := r.PreImage(newMockSet(4)); !got.Equals(newMockSet(1, 3)) {
+		t.Errorf("PreImage({4}) = %v, want {1, 3}", got)
+	}
+	if got, want := r.String(), "{(1, 1), (1, 4), (2, 3), (3, 1), (3, 4)}"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestRelationClosures(t *testing.T) {
+	// Rosen, section 9.4: R = {(1, 1), (1, 2), (2, 1), (3, 2)} on {1, 2, 3}.
+	r := relationOf([]int{1, 2, 3}, pairsOf([2]int{1, 1}, [2]int{1, 2}, [2]int{2, 1}, [2]int{3, 2})...)
+
+	reflexive := r.ReflexiveClosure()
+	if want := newMockSet(pairsOf([2]int{1, 1}, [2]int{1, 2}, [2]int{2, 1}, [2]int{2, 2}, [2]int{3, 2}, [2]int{3, 3})...); !reflexive.Pairs().Equals(want) {
+		t.Errorf("ReflexiveClosure() = %v, want %v", reflexive, want)
+	}
+	symmetric := r.SymmetricClosure()
+	if want := newMockSet(pairsOf([2]int{1, 1}, [2]int{1, 2}, [2]int{2, 1}, [2]int{2, 3}, [2]int{3, 2})...); !symmetric.Pairs().Equals(want) {
+		t.Errorf("SymmetricClosure() = %v, want %v", symmetric, want)
+	}
+	if !reflexive.IsReflexive() || !symmetric.IsSymmetric() || r.Cardinality() != 4 {
+		t.Error("closures lack their property or modified the relation")
+	}
+
+	// Rosen, section 9.4, example 8: Warshall's algorithm on {a, b, c, d}.
+	w := relationOf([]string{"a", "b", "c", "d"},
+		pairsOf([2]string{"a", "d"}, [2]string{"b", "a"}, [2]string{"b", "c"}, [2]string{"c", "a"}, [2]string{"c", "d"}, [2]string{"d", "c"})...)
+	closure := w.TransitiveClosure()
+	want := NewHashSet[Pair[string]]()
+	for _, a := range []string{"a", "b", "c", "d"} {
+		for _, b := range []string{"a", "c", "d"} {
+			want.Insert(Pair[string]{a, b})
+		}
+	}
+	if !closure.Pairs().Equals(want) {
+		t.Errorf("TransitiveClosure() = %v, want %v", closure, want)
+	}
+	if !closure.IsTransitive() || !closure.Pairs().IsSupersetOf(w.Pairs()) {
+		t.Error("TransitiveClosure() is not a transitive superset of the relation")
+	}
+
+	// The transitive closure of the successor relation is less than.
+	successor := NewRelation[int](nil, nil)
+	for i := range 50 {
+		successor.Add(i, i+1)
+	}
+	less := successor.TransitiveClosure()
+	if less.Cardinality() != 51*50/2 || !less.Related(0, 50) || less.Related(50, 0) || less.Related(7, 7) {
+		t.Errorf("TransitiveClosure() of the successor relation has %d pairs, want %d", less.Cardinality(), 51*50/2)
+	}
+}
+
+func TestRelationTransitiveClosureNaN(t *testing.T) {
+	// NaN cannot be looked up, so its pair is kept but not composed with
+	// others.
+	r := NewRelation[float64](nil, nil)
+	r.Add(math.NaN(), 1)
+	r.Add(0, 1)
+	r.Add(1, 2)
+	closure := r.TransitiveClosure()
+	if closure.Cardinality() != 4 {
+		t.Errorf("TransitiveClosure() = %v, want 4 pairs", closure)
+	}
+	for _, p := range [][2]float64{{0, 1}, {1, 2}, {0, 2}} {
+		if !closure.Related(p[0], p[1]) {
+			t.Errorf("TransitiveClosure() = %v, missing (%v, %v)", closure, p[0], p[1])
+		}
+	}
+	kept := false
+	for p := range closure.Pairs().All() {
+		kept = kept || math.IsNaN(p.First) && p.Second == 1
+	}
+	if !kept {
+		t.Errorf("TransitiveClosure() = %v, missing (NaN, 1)", closure)
+	}
+}
@@ -0,0 +1,245 @@
+package set
+
+import (
+	"fmt"
+	"html"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Notation selects the mathematical notation produced by Render and related
+// functions.
+type Notation int
+
+const (
+	// Unicode is plain text using the Unicode set symbols, such as
+	// {1, 2} × {3}, ∅ and {(x, y) | x ∈ A, y ∈ B}.
+	Unicode Notation = iota
+
+	// LaTeX is math-mode LaTeX, such as \{1, 2\} \times \{3\}, \emptyset and
+	// \{(x, y) \mid x \in A, y \in B\}. It does not include the surrounding
+	// math delimiters.
+	LaTeX
+
+	// MathML is a complete MathML <math> element.
+	MathML
+)
+
+// RenderOptions configures Render, RenderProduct and RenderProductBuilder.
+// The zero value renders every element in Unicode notation.
+type RenderOptions struct {
+	Notation Notation
+
+	// FormatElement, if not nil, returns the text of every element that is
+	// neither a FrozenSet nor a tuple. The text is inserted verbatim, so it
+	// must be valid in the chosen notation; this allows LaTeX macros such as
+	// \alpha. By default, numbers are written as digits, single letters as
+	// variables and other values as upright text.
+	FormatElement func(elem any) string
+
+	// MaxElements, if positive, limits the number of elements shown of each
+	// set, including nested sets. A larger set shows its first MaxElements-1
+	// elements, an ellipsis and its last element, such as {1, 2, …, 1000}.
+	MaxElements int
+}
+
+// notationSyntax holds the symbols of a notation.
+type notationSyntax struct {
+	open, close, separator, empty, ellipsis string
+	tupleOpen, tupleClose                   string
+	times, mid, in                          string
+	variables                               [2]string
+
+	// scalar renders an element that is neither a set nor a tuple.
+	scalar func(elem any) string
+
+	// group marks a nested expression, and document the whole output.
+	group, document func(string) string
+}
+
+var notations = [...]notationSyntax{
+	Unicode: {
+		open: "{", close: "}", separator: ", ", empty: "∅", ellipsis: "…",
+		tupleOpen: "(", tupleClose: ")",
+		times: " × ", mid: " | ", in: " ∈ ",
+		variables: [2]string{"x", "y"},
+		scalar:    formatElement,
+		group:     unchanged,
+		document:  unchanged,
+	},
+	LaTeX: {
+		open: `\{`, close: `\}`, separator: ", ", empty: `\emptyset`, ellipsis: `\ldots`,
+		tupleOpen: "(", tupleClose: ")",
+		times: ` \times `, mid: ` \mid `, in: ` \in `,
+		variables: [2]string{"x", "y"},
+		scalar:    latexScalar,
+		group:     unchanged,
+		document:  unchanged,
+	},
+	MathML: {
+		open: "<mo>{</mo>", close: "<mo>}</mo>", separator: "<mo>,</mo>", empty: "<mi>∅</mi>", ellipsis: "<mo>…</mo>",
+		tupleOpen: "<mo>(</mo>", tupleClose: "<mo>)</mo>",
+		times: "<mo>×</mo>", mid: "<mo>|</mo>", in: "<mo>∈</mo>",
+		variables: [2]string{"<mi>x</mi>", "<mi>y</mi>"},
+		scalar:    mathMLScalar,
+		group:     func(s string) string { return "<mrow>" + s + "</mrow>" },
+		document: func(s string) string {
+			return `<math xmlns="http://www.w3.org/1998/Math/MathML"><mrow>` + s + "</mrow></math>"
+		},
+	},
+}
+
+// nestedSet is implemented by the set types that can be elements of other
+// sets, so that they are rendered in the same notation as the outer set.
+type nestedSet interface {
+	displayElements() []any
+}
+
+func (f FrozenSet[T]) displayElements() []any {
+	return toAny(sortForDisplay(f.ToSlice()))
+}
+
+// Render returns the set in roster notation, listing its elements in the
+// order of String. Empty sets, including nested ones, are written with the
+// empty set sign, FrozenSet elements as nested sets and Pair, Pair2 and Triple
+// elements as tuples:
+//
+//	Render(s, RenderOptions{Notation: LaTeX}) // \{\emptyset, \{1, 2\}\}
+func Render[T comparable](s Set[T], opts RenderOptions) string {
+	r := newRenderer(opts)
+	return r.document(r.set(displayOrder(s)))
+}
+
+// RenderProduct returns the Cartesian product of a and b as the two sets
+// joined by the product sign, such as {1, 2} × {3, 4}. Use Render on the
+// result of CartesianProduct2 to list the pairs instead.
+func RenderProduct[A, B comparable](a Set[A], b Set[B], opts RenderOptions) string {
+	r := newRenderer(opts)
+	return r.document(r.set(displayOrder(a)) + r.times + r.set(displayOrder(b)))
+}
+
+// RenderProductBuilder returns the Cartesian product of a and b in
+// set-builder notation, such as {(x, y) | x ∈ {1, 2}, y ∈ {3, 4}}.
+func RenderProductBuilder[A, B comparable](a Set[A], b Set[B], opts RenderOptions) string {
+	r := newRenderer(opts)
+	x, y := r.variables[0], r.variables[1]
+	pair := r.group(r.tupleOpen + x + r.separator + y + r.tupleClose)
+	conditions := r.group(x+r.in+r.set(displayOrder(a))) + r.separator + r.group(y+r.in+r.set(displayOrder(b)))
+	return r.document(r.group(r.open + pair + r.mid + conditions + r.close))
+}
+
+// renderer renders elements in a notation.
+type renderer struct {
+	notationSyntax
+	opts RenderOptions
+}
+
+func newRenderer(opts RenderOptions) *renderer {
+	syntax := notations[Unicode]
+	if opts.Notation > 0 && int(opts.Notation) < len(notations) {
+		syntax = notations[opts.Notation]
+	}
+	return &renderer{notationSyntax: syntax, opts: opts}
+}
+
+// set renders a set with the elements elems, in display order.
+func (r *renderer) set(elems []any) string {
+	if len(elems) == 0 {
+		return r.empty
+	}
+	parts := make([]string, 0, len(elems))
+	if limit := r.opts.MaxElements; limit > 0 && len(elems) > limit {
+		for _, elem := range elems[:limit-1] {
+			parts = append(parts, r.element(elem))
+		}
+		parts = append(parts, r.ellipsis, r.element(elems[len(elems)-1]))
+	} else {
+		for _, elem := range elems {
+			parts = append(parts, r.element(elem))
+		}
+	}
+	return r.group(r.open + strings.Join(parts, r.separator) + r.close)
+}
+
+func (r *renderer) element(elem any) string {
+	switch e := elem.(type) {
+	case nestedSet:
+		return r.set(e.displayElements())
+	case tuple:
+		parts := make([]string, 0, 3)
+		for _, c := range e.components() {
+			parts = append(parts, r.element(c))
+		}
+		return r.group(r.tupleOpen + strings.Join(parts, r.separator) + r.tupleClose)
+	}
+	if r.opts.FormatElement != nil {
+		return r.opts.FormatElement(elem)
+	}
+	return r.scalar(elem)
+}
+
+// displayOrder returns the elements of s in the order of its String method.
+func displayOrder[T comparable](s Set[T]) []any {
+	if l, ok := s.(*LinkedHashSet[T]); ok {
+		return toAny(l.ToSlice())
+	}
+	return toAny(sortForDisplay(s.ToSlice()))
+}
+
+func toAny[T any](elems []T) []any {
+	result := make([]any, len(elems))
+	for i, elem := range elems {
+		result[i] = elem
+	}
+	return result
+}
+
+func unchanged(s string) string {
+	return s
+}
+
+// scalarKind classifies an element for the LaTeX and MathML notations: as a
+// number, a single letter, or other text.
+func scalarKind(elem any) (text string, number, letter bool) {
+	text = fmt.Sprint(elem)
+	switch reflect.ValueOf(elem).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		if _, ok := elem.(fmt.Stringer); !ok {
+			return text, true, false
+		}
+	}
+	r, size := utf8.DecodeRuneInString(text)
+	return text, false, size == len(text) && unicode.IsLetter(r)
+}
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "$", `\$`, "&", `\&`,
+	"#", `\#`, "_", `\_`, "%", `\%`, "^", `\^{}`, "~", `\~{}`,
+)
+
+// latexScalar writes numbers and single letters in math mode and anything
+// else as escaped upright text.
+func latexScalar(elem any) string {
+	text, number, letter := scalarKind(elem)
+	if number || letter {
+		return latexEscaper.Replace(text)
+	}
+	return `\text{` + latexEscaper.Replace(text) + "}"
+}
+
+// mathMLScalar writes numbers as <mn>, single letters as <mi> and anything
+// else as <mtext>.
+func mathMLScalar(elem any) string {
+	text, number, letter := scalarKind(elem)
+	switch {
+	case number:
+		return "<mn>" + html.EscapeString(text) + "</mn>"
+	case letter:
+		return "<mi>" + html.EscapeString(text) + "</mi>"
+	}
+	return "<mtext>" + html.EscapeString(text) + "</mtext>"
+}
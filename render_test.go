@@ -0,0 +1,86 @@
+package set
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	nested := hashSetOf(NewFrozenSet(2, 1), NewFrozenSet[int]())
+	thousand := NewBitSet()
+	for i := uint(1); i <= 1000; i++ {
+		thousand.Insert(i)
+	}
+	product := CartesianProduct2(hashSetOf(1, 2), hashSetOf("a"))
+	words := hashSetOf("x", "a_b", "50%")
+
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"unicode empty", Render(NewHashSet[int](), RenderOptions{}), "∅"},
+		{"unicode nested", Render(nested, RenderOptions{}), "{{1, 2}, ∅}"},
+		{"unicode pairs", Render(product, RenderOptions{}), "{(1, a), (2, a)}"},
+		{"unicode truncated", Render[uint](thousand, RenderOptions{MaxElements: 3}), "{1, 2, …, 1000}"},
+		{"unicode insertion order", Render[int](newLinkedHashSet(3, 1, 2), RenderOptions{}), "{3, 1, 2}"},
+		{"latex empty", Render(NewHashSet[int](), RenderOptions{Notation: LaTeX}), `\emptyset`},
+		{"latex nested", Render(nested, RenderOptions{Notation: LaTeX}), `\{\{1, 2\}, \emptyset\}`},
+		{"latex text", Render(words, RenderOptions{Notation: LaTeX}), `\{\text{50\%}, \text{a\_b}, x\}`},
+		{"latex truncated", Render[uint](thousand, RenderOptions{Notation: LaTeX, MaxElements: 2}), `\{1, \ldots, 1000\}`},
+		{"latex pairs", Render(product, RenderOptions{Notation: LaTeX}), `\{(1, a), (2, a)\}`},
+		{
+			"latex custom elements",
+			Render(hashSetOf(1, 2), RenderOptions{Notation: LaTeX, FormatElement: func(elem any) string {
+				return `\alpha_` + strconv.Itoa(elem.(int))
+			}}),
+			`\{\alpha_1, \alpha_2\}`,
+		},
+		{
+			"mathml",
+			Render(hashSetOf[any](1, "x", "a<b"), RenderOptions{Notation: MathML}),
+			`<math xmlns="http://www.w3.org/1998/Math/MathML"><mrow><mrow><mo>{</mo><mn>1</mn><mo>,</mo><mtext>a&lt;b</mtext><mo>,</mo><mi>x</mi><mo>}</mo></mrow></mrow></math>`,
+		},
+		{
+			"mathml nested",
+			Render(hashSetOf(NewFrozenSet[int]()), RenderOptions{Notation: MathML}),
+			`<math xmlns="http://www.w3.org/1998/Math/MathML"><mrow><mrow><mo>{</mo><mi>∅</mi><mo>}</mo></mrow></mrow></math>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("Render() = %q, want %q", tt.got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderProduct(t *testing.T) {
+	a, b := hashSetOf(2, 1), hashSetOf("y")
+
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"unicode", RenderProduct(a, b, RenderOptions{}), "{1, 2} × {y}"},
+		{"latex", RenderProduct(a, b, RenderOptions{Notation: LaTeX}), `\{1, 2\} \times \{y\}`},
+		{"unicode builder", RenderProductBuilder(a, b, RenderOptions{}), "{(x, y) | x ∈ {1, 2}, y ∈ {y}}"},
+		{"latex builder", RenderProductBuilder(a, NewHashSet[string](), RenderOptions{Notation: LaTeX}), `\{(x, y) \mid x \in \{1, 2\}, y \in \emptyset\}`},
+		{
+			"mathml",
+			RenderProduct(a, b, RenderOptions{Notation: MathML}),
+			`<math xmlns="http://www.w3.org/1998/Math/MathML"><mrow><mrow><mo>{</mo><mn>1</mn><mo>,</mo><mn>2</mn><mo>}</mo></mrow><mo>×</mo><mrow><mo>{</mo><mi>y</mi><mo>}</mo></mrow></mrow></math>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("got %q, want %q", tt.got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,733 @@
+package set
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// Roaring containers hold the low 16 bits of the elements sharing the same
+// high 16 bits. Each container uses whichever of three representations suits
+// its contents:
+//
+//   - an array container stores up to arrayContainerMax sorted values,
+//   - a bitmap container stores more values as a fixed 2^16-bit bitmap,
+//   - a run container stores sorted runs of consecutive values.
+const (
+	// arrayContainerMax is the largest cardinality stored in an array
+	// container. Above it, a bitmap container uses less memory.
+	arrayContainerMax = 4096
+
+	// bitmapContainerWords is the number of 64-bit words covering 2^16 values.
+	bitmapContainerWords = 1 << 16 / 64
+)
+
+// container is the interface implemented by the three container kinds. Methods
+// that modify a container return the container to use from then on, which may
+// be of a different kind.
+type container interface {
+	contains(x uint16) bool
+	add(x uint16) container
+	remove(x uint16) container
+	cardinality() int
+	numRuns() int
+	iterate(yield func(uint16) bool) bool
+}
+
+// arrayContainer stores a sorted slice of distinct values.
+type arrayContainer struct {
+	values []uint16
+}
+
+// bitmapContainer stores values as a bitmap and caches its cardinality.
+type bitmapContainer struct {
+	words [bitmapContainerWords]uint64
+	card  int
+}
+
+// interval16 is the run of consecutive values start, start+1, ..., start+length.
+// The length is stored minus one, as in the Roaring format, so that a run can
+// cover all 2^16 values.
+type interval16 struct {
+	start  uint16
+	length uint16
+}
+
+func (iv interval16) last() int {
+	return int(iv.start) + int(iv.length)
+}
+
+// runContainer stores sorted, non-overlapping and non-adjacent runs.
+type runContainer struct {
+	runs []interval16
+}
+
+func (a *arrayContainer) contains(x uint16) bool {
+	_, found := slices.BinarySearch(a.values, x)
+	return found
+}
+
+func (a *arrayContainer) add(x uint16) container {
+	i, found := slices.BinarySearch(a.values, x)
+	if found {
+		return a
+	}
+	if len(a.values) >= arrayContainerMax {
+		b := a.toBitmap()
+		return b.add(x)
+	}
+	a.values = slices.Insert(a.values, i, x)
+	return a
+}
+
+func (a *arrayContainer) remove(x uint16) container {
+	if i, found := slices.BinarySearch(a.values, x); found {
+		a.values = slices.Delete(a.values, i, i+1)
+	}
+	return a
+}
+
+func (a *arrayContainer) cardinality() int {
+	return len(a.values)
+}
+
+func (a *arrayContainer) numRuns() int {
+	runs := 0
+	for i, v := range a.values {
+		if i == 0 || v != a.values[i-1]+1 {
+			runs++
+		}
+	}
+	return runs
+}
+
+func (a *arrayContainer) iterate(yield func(uint16) bool) bool {
+	for _, v := range a.values {
+		if !yield(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *arrayContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, v := range a.values {
+		b.words[v/64] |= 1 << (v % 64)
+	}
+	b.card = len(a.values)
+	return b
+}
+
+func (b *bitmapContainer) contains(x uint16) bool {
+	return b.words[x/64]&(1<<(x%64)) != 0
+}
+
+func (b *bitmapContainer) add(x uint16) container {
+	if !b.contains(x) {
+		b.words[x/64] |= 1 << (x % 64)
+		b.card++
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(x uint16) container {
+	if !b.contains(x) {
+		return b
+	}
+	b.words[x/64] &^= 1 << (x % 64)
+	b.card--
+	if b.card <= arrayContainerMax {
+		return b.toArray()
+	}
+	return b
+}
+
+func (b *bitmapContainer) cardinality() int {
+	return b.card
+}
+
+func (b *bitmapContainer) numRuns() int {
+	runs := 0
+	var carry uint64
+	for _, w := range b.words {
+		// A run starts at every set bit whose lower neighbour is clear.
+		runs += bits.OnesCount64(w &^ (w<<1 | carry))
+		carry = w >> 63
+	}
+	return runs
+}
+
+func (b *bitmapContainer) iterate(yield func(uint16) bool) bool {
+	for i, w := range b.words {
+		for w != 0 {
+			if !yield(uint16(i*64 + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+func (b *bitmapContainer) toArray() *arrayContainer {
+	a := &arrayContainer{values: make([]uint16, 0, b.card)}
+	b.iterate(func(v uint16) bool {
+		a.values = append(a.values, v)
+		return true
+	})
+	return a
+}
+
+// find returns the index of the last run starting at or before x, or -1.
+func (r *runContainer) find(x uint16) int {
+	i, _ := slices.BinarySearchFunc(r.runs, x, func(iv interval16, x uint16) int {
+		if iv.start <= x {
+			return -1
+		}
+		return 1
+	})
+	return i - 1
+}
+
+func (r *runContainer) contains(x uint16) bool {
+	i := r.find(x)
+	return i >= 0 && int(x) <= r.runs[i].last()
+}
+
+func (r *runContainer) add(x uint16) container {
+	i := r.find(x)
+	if i >= 0 && int(x) <= r.runs[i].last() {
+		return r
+	}
+
+	extendsPrev := i >= 0 && r.runs[i].last()+1 == int(x)
+	extendsNext := i+1 < len(r.runs) && int(r.runs[i+1].start) == int(x)+1
+	switch {
+	case extendsPrev && extendsNext:
+		// x fills the gap between two runs, which merge into one.
+		r.runs[i].length += r.runs[i+1].length + 2
+		r.runs = slices.Delete(r.runs, i+1, i+2)
+	case extendsPrev:
+		r.runs[i].length++
+	case extendsNext:
+		r.runs[i+1].start--
+		r.runs[i+1].length++
+	default:
+		r.runs = slices.Insert(r.runs, i+1, interval16{start: x})
+	}
+	return r
+}
+
+func (r *runContainer) remove(x uint16) container {
+	i := r.find(x)
+	if i < 0 || int(x) > r.runs[i].last() {
+		return r
+	}
+
+	iv := r.runs[i]
+	switch {
+	case iv.length == 0:
+		r.runs = slices.Delete(r.runs, i, i+1)
+	case x == iv.start:
+		r.runs[i].start++
+		r.runs[i].length--
+	case int(x) == iv.last():
+		r.runs[i].length--
+	default:
+		// Split the run around x.
+		r.runs[i].length = x - iv.start - 1
+		r.runs = slices.Insert(r.runs, i+1, interval16{start: x + 1, length: uint16(iv.last() - int(x) - 1)})
+	}
+	return r
+}
+
+func (r *runContainer) cardinality() int {
+	card := 0
+	for _, iv := range r.runs {
+		card += int(iv.length) + 1
+	}
+	return card
+}
+
+func (r *runContainer) numRuns() int {
+	return len(r.runs)
+}
+
+func (r *runContainer) iterate(yield func(uint16) bool) bool {
+	for _, iv := range r.runs {
+		for v := int(iv.start); v <= iv.last(); v++ {
+			if !yield(uint16(v)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// toRuns returns a run container holding the values of c.
+func toRuns(c container) *runContainer {
+	r := &runContainer{runs: make([]interval16, 0, c.numRuns())}
+	c.iterate(func(v uint16) bool {
+		if n := len(r.runs); n > 0 && r.runs[n-1].last()+1 == int(v) {
+			r.runs[n-1].length++
+		} else {
+			r.runs = append(r.runs, interval16{start: v})
+		}
+		return true
+	})
+	return r
+}
+
+// toBitmap returns the values of c as a bitmap container. A bitmap container
+// is returned as is and must not be modified by the caller.
+func toBitmap(c container) *bitmapContainer {
+	switch c := c.(type) {
+	case *bitmapContainer:
+		return c
+	case *arrayContainer:
+		return c.toBitmap()
+	}
+	b := &bitmapContainer{}
+	c.iterate(func(v uint16) bool {
+		b.words[v/64] |= 1 << (v % 64)
+		return true
+	})
+	b.card = c.cardinality()
+	return b
+}
+
+// fromValues returns a container for sorted, distinct values, or nil if
+// there are none.
+func fromValues(values []uint16) container {
+	switch {
+	case len(values) == 0:
+		return nil
+	case len(values) <= arrayContainerMax:
+		return &arrayContainer{values: values}
+	}
+	return (&arrayContainer{values: values}).toBitmap()
+}
+
+// fromWords returns a container for the bitmap words, or nil if they are all
+// zero. Sparse results are converted to array containers.
+func fromWords(words *[bitmapContainerWords]uint64) container {
+	b := &bitmapContainer{words: *words}
+	for _, w := range b.words {
+		b.card += bits.OnesCount64(w)
+	}
+	switch {
+	case b.card == 0:
+		return nil
+	case b.card <= arrayContainerMax:
+		return b.toArray()
+	}
+	return b
+}
+
+// filter returns the values of a for which keep reports true.
+func filter(a *arrayContainer, keep func(uint16) bool) container {
+	result := make([]uint16, 0, len(a.values))
+	for _, v := range a.values {
+		if keep(v) {
+			result = append(result, v)
+		}
+	}
+	return fromValues(result)
+}
+
+// combineWords applies op to the bitmaps of a and b word by word.
+func combineWords(a, b container, op func(x, y uint64) uint64) container {
+	aw, bw := &toBitmap(a).words, &toBitmap(b).words
+	var words [bitmapContainerWords]uint64
+	for i := range words {
+		words[i] = op(aw[i], bw[i])
+	}
+	return fromWords(&words)
+}
+
+// The container operations below pick a strategy by container kind: two array
+// containers are merged as sorted sequences, an array container is filtered
+// against any other container by membership, and every other combination is
+// computed on bitmaps word by word. They return nil for an empty result.
+
+func containerAnd(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	switch {
+	case aIsArray && bIsArray:
+		return fromValues(merge(aa.values, ba.values, false, false, true))
+	case aIsArray:
+		return filter(aa, b.contains)
+	case bIsArray:
+		return filter(ba, a.contains)
+	}
+	return combineWords(a, b, func(x, y uint64) uint64 { return x & y })
+}
+
+func containerOr(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	if aIsArray && bIsArray {
+		return fromValues(merge(aa.values, ba.values, true, true, true))
+	}
+	return combineWords(a, b, func(x, y uint64) uint64 { return x | y })
+}
+
+func containerAndNot(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	switch {
+	case aIsArray && bIsArray:
+		return fromValues(merge(aa.values, ba.values, true, false, false))
+	case aIsArray:
+		return filter(aa, func(v uint16) bool { return !b.contains(v) })
+	}
+	return combineWords(a, b, func(x, y uint64) uint64 { return x &^ y })
+}
+
+func containerXor(a, b container) container {
+	aa, aIsArray := a.(*arrayContainer)
+	ba, bIsArray := b.(*arrayContainer)
+	if aIsArray && bIsArray {
+		return fromValues(merge(aa.values, ba.values, true, true, false))
+	}
+	return combineWords(a, b, func(x, y uint64) uint64 { return x ^ y })
+}
+
+// cloneContainer returns a deep copy of c.
+func cloneContainer(c container) container {
+	switch c := c.(type) {
+	case *arrayContainer:
+		return &arrayContainer{values: slices.Clone(c.values)}
+	case *bitmapContainer:
+		clone := *c
+		return &clone
+	case *runContainer:
+		return &runContainer{runs: slices.Clone(c.runs)}
+	}
+	panic(fmt.Sprintf("set: unknown roaring container %T", c))
+}
+
+// RoaringBitmap implements the Set interface for 32-bit unsigned integers using
+// compressed Roaring bitmaps. The 32-bit space is split into 2^16 chunks by
+// the high 16 bits of each element, and the elements of every non-empty chunk
+// are stored in a container chosen for its density: a sorted array for sparse
+// chunks, a bitmap for dense chunks, or a list of runs of consecutive values
+// after RunOptimize. Sparse sets spread over the whole uint32 range therefore
+// stay compact, unlike with BitSet.
+//
+// Set algebra between two RoaringBitmaps works chunk by chunk with a strategy
+// suited to each pair of containers. WriteTo and ReadFrom use the portable
+// Roaring serialization format, so serialized bitmaps can be exchanged with
+// Roaring libraries for other languages.
+//
+// The zero value of a RoaringBitmap is an empty set ready to use.
+type RoaringBitmap struct {
+	keys       []uint16
+	containers []container
+}
+
+// NewRoaringBitmap creates and returns a new empty Roaring bitmap.
+func NewRoaringBitmap() *RoaringBitmap {
+	return &RoaringBitmap{}
+}
+
+func (r *RoaringBitmap) emptyLike() Set[uint32] {
+	return NewRoaringBitmap()
+}
+
+func splitRoaring(elem uint32) (high, low uint16) {
+	return uint16(elem >> 16), uint16(elem)
+}
+
+func (r *RoaringBitmap) Insert(elem uint32) {
+	high, low := splitRoaring(elem)
+	i, found := slices.BinarySearch(r.keys, high)
+	if !found {
+		r.keys = slices.Insert(r.keys, i, high)
+		r.containers = slices.Insert(r.containers, i, container(&arrayContainer{values: []uint16{low}}))
+		return
+	}
+	r.containers[i] = r.containers[i].add(low)
+}
+
+func (r *RoaringBitmap) Remove(elem uint32) {
+	high, low := splitRoaring(elem)
+	i, found := slices.BinarySearch(r.keys, high)
+	if !found {
+		return
+	}
+	r.containers[i] = r.containers[i].remove(low)
+	if r.containers[i].cardinality() == 0 {
+		r.keys = slices.Delete(r.keys, i, i+1)
+		r.containers = slices.Delete(r.containers, i, i+1)
+	}
+}
+
+func (r *RoaringBitmap) InsertAll(elems ...uint32) int {
+	return insertAll(r, slices.Values(elems))
+}
+
+func (r *RoaringBitmap) RemoveAll(elems ...uint32) int {
+	return removeAll(r, slices.Values(elems))
+}
+
+// RetainIf deletes every element for which keep returns false and returns the
+// number of elements deleted. keep is called in ascending order.
+func (r *RoaringBitmap) RetainIf(keep func(uint32) bool) int {
+	return retainIf(r, keep)
+}
+
+// RemoveIf deletes every element for which remove returns true and returns the
+// number of elements deleted. remove is called in ascending order.
+func (r *RoaringBitmap) RemoveIf(remove func(uint32) bool) int {
+	return removeIf(r, remove)
+}
+
+func (r *RoaringBitmap) Clear() int {
+	n := r.Cardinality()
+	r.keys, r.containers = nil, nil
+	return n
+}
+
+// Pop deletes the smallest element of the set and returns it.
+// The boolean is false if the set is empty.
+func (r *RoaringBitmap) Pop() (uint32, bool) {
+	return pop(r)
+}
+
+func (r *RoaringBitmap) Contains(elem uint32) bool {
+	high, low := splitRoaring(elem)
+	i, found := slices.BinarySearch(r.keys, high)
+	return found && r.containers[i].contains(low)
+}
+
+func (r *RoaringBitmap) Cardinality() int {
+	card := 0
+	for _, c := range r.containers {
+		card += c.cardinality()
+	}
+	return card
+}
+
+func (r *RoaringBitmap) IsEmpty() bool {
+	return len(r.keys) == 0
+}
+
+// RunOptimize converts every container to the representation that takes the
+// least space, using run containers where values form long runs of consecutive
+// integers. It reports whether any container is run-encoded afterwards.
+func (r *RoaringBitmap) RunOptimize() bool {
+	hasRuns := false
+	for i, c := range r.containers {
+		card := c.cardinality()
+		runBytes := 2 + 4*c.numRuns()
+		otherBytes := 2 * card
+		if card > arrayContainerMax {
+			otherBytes = 8 * bitmapContainerWords
+		}
+
+		switch _, isRun := c.(*runContainer); {
+		case runBytes < otherBytes:
+			if !isRun {
+				r.containers[i] = toRuns(c)
+			}
+			hasRuns = true
+		case isRun && card > arrayContainerMax:
+			r.containers[i] = toBitmap(c)
+		case isRun:
+			r.containers[i] = toBitmap(c).toArray()
+		}
+	}
+	return hasRuns
+}
+
+// All returns an iterator over the elements of the set in ascending order.
+func (r *RoaringBitmap) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range r.containers {
+			base := uint32(r.keys[i]) << 16
+			if !c.iterate(func(low uint16) bool { return yield(base | uint32(low)) }) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice returns a slice containing all elements in the set in ascending order.
+func (r *RoaringBitmap) ToSlice() []uint32 {
+	result := make([]uint32, 0, r.Cardinality())
+	for elem := range r.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+// String returns a string representation of the set with its elements in
+// ascending order.
+func (r *RoaringBitmap) String() string {
+	return formatLiteral(r.ToSlice(), formatElement)
+}
+
+// Format implements fmt.Formatter, listing the elements in ascending order.
+func (r *RoaringBitmap) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, r.ToSlice(), "set.NewRoaringBitmap()", "*set.RoaringBitmap")
+}
+
+func (r *RoaringBitmap) Equals(other Set[uint32]) bool {
+	o, ok := other.(*RoaringBitmap)
+	if !ok {
+		return equal(r, other)
+	}
+	return slices.Equal(r.keys, o.keys) && r.IsSubsetOf(o) && r.Cardinality() == o.Cardinality()
+}
+
+func (r *RoaringBitmap) IsSubsetOf(other Set[uint32]) bool {
+	o, ok := other.(*RoaringBitmap)
+	if !ok {
+		return isSubset(r, other)
+	}
+
+	for i, key := range r.keys {
+		j, found := slices.BinarySearch(o.keys, key)
+		if !found || containerAndNot(r.containers[i], o.containers[j]) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RoaringBitmap) IsSupersetOf(other Set[uint32]) bool {
+	if o, ok := other.(*RoaringBitmap); ok {
+		return o.IsSubsetOf(r)
+	}
+	return isSubset(other, r)
+}
+
+func (r *RoaringBitmap) IsProperSubsetOf(other Set[uint32]) bool {
+	return r.Cardinality() < other.Cardinality() && r.IsSubsetOf(other)
+}
+
+func (r *RoaringBitmap) IsProperSupersetOf(other Set[uint32]) bool {
+	return r.Cardinality() > other.Cardinality() && r.IsSupersetOf(other)
+}
+
+// combine merges the chunks of r and o by key. Chunks present in only one
+// operand are copied when keepOnlyR or keepOnlyO is set; chunks present in
+// both are combined with op.
+func (r *RoaringBitmap) combine(o *RoaringBitmap, keepOnlyR, keepOnlyO bool, op func(a, b container) container) *RoaringBitmap {
+	result := &RoaringBitmap{}
+	appendContainer := func(key uint16, c container) {
+		if c != nil {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, c)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(r.keys) || j < len(o.keys) {
+		switch {
+		case j == len(o.keys) || (i < len(r.keys) && r.keys[i] < o.keys[j]):
+			if keepOnlyR {
+				appendContainer(r.keys[i], cloneContainer(r.containers[i]))
+			}
+			i++
+		case i == len(r.keys) || o.keys[j] < r.keys[i]:
+			if keepOnlyO {
+				appendContainer(o.keys[j], cloneContainer(o.containers[j]))
+			}
+			j++
+		default:
+			appendContainer(r.keys[i], op(r.containers[i], o.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Union returns a new RoaringBitmap containing all elements that are in either
+// this set or the other set (**X** ∪ **Y**).
+func (r *RoaringBitmap) Union(other Set[uint32]) Set[uint32] {
+	if o, ok := other.(*RoaringBitmap); ok {
+		return r.combine(o, true, true, containerOr)
+	}
+	return unionInto(NewRoaringBitmap(), r, other)
+}
+
+// Intersection returns a new RoaringBitmap containing all elements that are in
+// both this set and the other set (**X** ∩ **Y**).
+func (r *RoaringBitmap) Intersection(other Set[uint32]) Set[uint32] {
+	if o, ok := other.(*RoaringBitmap); ok {
+		return r.combine(o, false, false, containerAnd)
+	}
+	return intersectionInto(NewRoaringBitmap(), r, other)
+}
+
+// Difference returns a new RoaringBitmap containing all elements that are in
+// this set but not in the other set (**X** \ **Y**).
+func (r *RoaringBitmap) Difference(other Set[uint32]) Set[uint32] {
+	if o, ok := other.(*RoaringBitmap); ok {
+		return r.combine(o, true, false, containerAndNot)
+	}
+	return differenceInto(NewRoaringBitmap(), r, other)
+}
+
+// SymmetricDifference returns a new RoaringBitmap containing all elements that
+// are in either this set or the other set, but not in both (**X** Δ **Y**).
+func (r *RoaringBitmap) SymmetricDifference(other Set[uint32]) Set[uint32] {
+	if o, ok := other.(*RoaringBitmap); ok {
+		return r.combine(o, true, true, containerXor)
+	}
+	return symmetricDifferenceInto(NewRoaringBitmap(), r, other)
+}
+
+// combineInPlace replaces r with the result of combine and returns the number
+// of elements added or removed.
+func (r *RoaringBitmap) combineInPlace(o *RoaringBitmap, keepOnlyR, keepOnlyO bool, op func(a, b container) container) int {
+	before := r.Cardinality()
+	*r = *r.combine(o, keepOnlyR, keepOnlyO, op)
+	after := r.Cardinality()
+	return max(before-after, after-before)
+}
+
+// UnionWith adds the elements of the other set to this set in place
+// (**X** ← **X** ∪ **Y**) and returns the number of elements added.
+func (r *RoaringBitmap) UnionWith(other Set[uint32]) int {
+	if o, ok := other.(*RoaringBitmap); ok {
+		return r.combineInPlace(o, true, true, containerOr)
+	}
+	return insertAll(r, other.All())
+}
+
+// IntersectWith deletes the elements of this set that are not in the other set
+// (**X** ← **X** ∩ **Y**) and returns the number of elements deleted.
+func (r *RoaringBitmap) IntersectWith(other Set[uint32]) int {
+	if o, ok := other.(*RoaringBitmap); ok {
+		return r.combineInPlace(o, false, false, containerAnd)
+	}
+	return retainIf(r, other.Contains)
+}
+
+// DifferenceWith deletes the elements of this set that are in the other set
+// (**X** ← **X** \ **Y**) and returns the number of elements deleted.
+func (r *RoaringBitmap) DifferenceWith(other Set[uint32]) int {
+	if o, ok := other.(*RoaringBitmap); ok {
+		return r.combineInPlace(o, true, false, containerAndNot)
+	}
+	return differenceWith(r, other)
+}
+
+// SymmetricDifferenceWith deletes the elements of this set that are in the
+// other set and adds those that are not (**X** ← **X** Δ **Y**). It returns the
+// number of elements added or deleted.
+func (r *RoaringBitmap) SymmetricDifferenceWith(other Set[uint32]) int {
+	if o, ok := other.(*RoaringBitmap); ok {
+		n := o.Cardinality()
+		*r = *r.combine(o, true, true, containerXor)
+		return n
+	}
+	return symmetricDifferenceWith(r, other)
+}
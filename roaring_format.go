@@ -0,0 +1,326 @@
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Constants of the portable Roaring serialization format, as described in
+// https://github.com/RoaringBitmap/RoaringFormatSpec. All values are
+// little-endian.
+const (
+	// serialCookieNoRuns starts bitmaps without run containers. It is followed
+	// by the number of containers as a 32-bit integer.
+	serialCookieNoRuns = 12346
+
+	// serialCookie starts bitmaps with at least one run container. The number
+	// of containers minus one is stored in its upper 16 bits and it is followed
+	// by a bitset marking which containers are run containers.
+	serialCookie = 12347
+
+	// noOffsetThreshold is the number of containers below which bitmaps with
+	// run containers omit the offset header.
+	noOffsetThreshold = 4
+)
+
+func invalidRoaring(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRoaringFormat, fmt.Sprintf(format, args...))
+}
+
+// serializedSize returns the number of bytes used by c in the serialized form.
+func serializedSize(c container) int {
+	switch c := c.(type) {
+	case *arrayContainer:
+		return 2 * len(c.values)
+	case *runContainer:
+		return 2 + 4*len(c.runs)
+	}
+	return 8 * bitmapContainerWords
+}
+
+// WriteTo writes the bitmap to w in the portable Roaring serialization format
+// and returns the number of bytes written. It implements io.WriterTo.
+func (r *RoaringBitmap) WriteTo(w io.Writer) (int64, error) {
+	size := len(r.keys)
+	hasRuns := false
+	for _, c := range r.containers {
+		if _, ok := c.(*runContainer); ok {
+			hasRuns = true
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+
+	// Cookie header.
+	if hasRuns {
+		buf.Write(le.AppendUint32(nil, serialCookie|uint32(size-1)<<16))
+		runFlags := make([]byte, (size+7)/8)
+		for i, c := range r.containers {
+			if _, ok := c.(*runContainer); ok {
+				runFlags[i/8] |= 1 << (i % 8)
+			}
+		}
+		buf.Write(runFlags)
+	} else {
+		buf.Write(le.AppendUint32(nil, serialCookieNoRuns))
+		buf.Write(le.AppendUint32(nil, uint32(size)))
+	}
+
+	// Descriptive header: key and cardinality minus one of every container.
+	for i, c := range r.containers {
+		buf.Write(le.AppendUint16(nil, r.keys[i]))
+		buf.Write(le.AppendUint16(nil, uint16(c.cardinality()-1)))
+	}
+
+	// Offset header: the position of every container from the start.
+	if !hasRuns || size >= noOffsetThreshold {
+		offset := buf.Len() + 4*size
+		for _, c := range r.containers {
+			buf.Write(le.AppendUint32(nil, uint32(offset)))
+			offset += serializedSize(c)
+		}
+	}
+
+	for _, c := range r.containers {
+		switch c := c.(type) {
+		case *arrayContainer:
+			for _, v := range c.values {
+				buf.Write(le.AppendUint16(nil, v))
+			}
+		case *bitmapContainer:
+			for _, word := range c.words {
+				buf.Write(le.AppendUint64(nil, word))
+			}
+		case *runContainer:
+			buf.Write(le.AppendUint16(nil, uint16(len(c.runs))))
+			for _, iv := range c.runs {
+				buf.Write(le.AppendUint16(nil, iv.start))
+				buf.Write(le.AppendUint16(nil, iv.length))
+			}
+		}
+	}
+
+	return buf.WriteTo(w)
+}
+
+// roaringReader reads little-endian values and counts the bytes consumed.
+type roaringReader struct {
+	r   io.Reader
+	n   int64
+	buf [8]byte
+}
+
+func (rr *roaringReader) read(p []byte) error {
+	n, err := io.ReadFull(rr.r, p)
+	rr.n += int64(n)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return invalidRoaring("unexpected end of data")
+	}
+	return err
+}
+
+func (rr *roaringReader) uint16() (uint16, error) {
+	err := rr.read(rr.buf[:2])
+	return binary.LittleEndian.Uint16(rr.buf[:2]), err
+}
+
+func (rr *roaringReader) uint32() (uint32, error) {
+	err := rr.read(rr.buf[:4])
+	return binary.LittleEndian.Uint32(rr.buf[:4]), err
+}
+
+func (rr *roaringReader) uint64() (uint64, error) {
+	err := rr.read(rr.buf[:8])
+	return binary.LittleEndian.Uint64(rr.buf[:8]), err
+}
+
+// ReadFrom replaces the contents of the bitmap with a bitmap read from r in
+// the portable Roaring serialization format, and returns the number of bytes
+// read. It implements io.ReaderFrom. Malformed input results in an error
+// wrapping ErrInvalidRoaringFormat, in which case the bitmap is left unchanged.
+func (r *RoaringBitmap) ReadFrom(reader io.Reader) (int64, error) {
+	rr := &roaringReader{r: reader}
+	decoded, err := rr.readBitmap()
+	if err != nil {
+		return rr.n, err
+	}
+	*r = *decoded
+	return rr.n, nil
+}
+
+func (rr *roaringReader) readBitmap() (*RoaringBitmap, error) {
+	cookie, err := rr.uint32()
+	if err != nil {
+		return nil, err
+	}
+
+	var size int
+	var runFlags []byte
+	switch {
+	case cookie&0xFFFF == serialCookie:
+		size = int(cookie>>16) + 1
+		runFlags = make([]byte, (size+7)/8)
+		if err := rr.read(runFlags); err != nil {
+			return nil, err
+		}
+	case cookie == serialCookieNoRuns:
+		n, err := rr.uint32()
+		if err != nil {
+			return nil, err
+		}
+		if n > 1<<16 {
+			return nil, invalidRoaring("%d containers exceed the maximum of %d", n, 1<<16)
+		}
+		size = int(n)
+	default:
+		return nil, invalidRoaring("unknown cookie %d", cookie)
+	}
+
+	keys := make([]uint16, size)
+	cards := make([]int, size)
+	for i := range size {
+		if keys[i], err = rr.uint16(); err != nil {
+			return nil, err
+		}
+		if i > 0 && keys[i] <= keys[i-1] {
+			return nil, invalidRoaring("container keys are not strictly increasing")
+		}
+		card, err := rr.uint16()
+		if err != nil {
+			return nil, err
+		}
+		cards[i] = int(card) + 1
+	}
+
+	// The offsets are only needed for random access; containers are read in
+	// order, so they are skipped.
+	if runFlags == nil || size >= noOffsetThreshold {
+		for range size {
+			if _, err := rr.uint32(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := &RoaringBitmap{keys: keys, containers: make([]container, size)}
+	for i := range size {
+		isRun := runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0
+		var c container
+		switch {
+		case isRun:
+			c, err = rr.readRunContainer()
+		case cards[i] > arrayContainerMax:
+			c, err = rr.readBitmapContainer()
+		default:
+			c, err = rr.readArrayContainer(cards[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c.cardinality() != cards[i] {
+			return nil, invalidRoaring("container %d has %d elements, header says %d", i, c.cardinality(), cards[i])
+		}
+		result.containers[i] = c
+	}
+	return result, nil
+}
+
+func (rr *roaringReader) readArrayContainer(card int) (container, error) {
+	values := make([]uint16, card)
+	for i := range values {
+		v, err := rr.uint16()
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 && v <= values[i-1] {
+			return nil, invalidRoaring("array container values are not strictly increasing")
+		}
+		values[i] = v
+	}
+	return &arrayContainer{values: values}, nil
+}
+
+func (rr *roaringReader) readBitmapContainer() (container, error) {
+	var words [bitmapContainerWords]uint64
+	for i := range words {
+		w, err := rr.uint64()
+		if err != nil {
+			return nil, err
+		}
+		words[i] = w
+	}
+	// A bitmap holding few enough values for an array container disagrees
+	// with the header, which the caller reports as a cardinality mismatch.
+	c := fromWords(&words)
+	if c == nil {
+		return nil, invalidRoaring("empty bitmap container")
+	}
+	return c, nil
+}
+
+func (rr *roaringReader) readRunContainer() (container, error) {
+	n, err := rr.uint16()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, invalidRoaring("empty run container")
+	}
+	runs := make([]interval16, 0, n)
+	for range n {
+		var iv interval16
+		if iv.start, err = rr.uint16(); err != nil {
+			return nil, err
+		}
+		if iv.length, err = rr.uint16(); err != nil {
+			return nil, err
+		}
+		if iv.last() > 0xFFFF {
+			return nil, invalidRoaring("run exceeds the container range")
+		}
+
+		// Adjacent runs are valid but are merged, as run containers never
+		// hold two runs that touch.
+		last := len(runs) - 1
+		switch {
+		case last >= 0 && int(iv.start) <= runs[last].last():
+			return nil, invalidRoaring("runs overlap or are not sorted")
+		case last >= 0 && int(iv.start) == runs[last].last()+1:
+			runs[last].length += iv.length + 1
+		default:
+			runs = append(runs, iv)
+		}
+	}
+	return &runContainer{runs: runs}, nil
+}
+
+// MarshalBinary encodes the bitmap in the portable Roaring serialization
+// format. It implements encoding.BinaryMarshaler.
+func (r *RoaringBitmap) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := r.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a bitmap in the portable Roaring serialization
+// format, replacing the contents of r. It implements
+// encoding.BinaryUnmarshaler. Trailing data after the bitmap is an error.
+func (r *RoaringBitmap) UnmarshalBinary(data []byte) error {
+	reader := bytes.NewReader(data)
+	decoded, err := (&roaringReader{r: reader}).readBitmap()
+	if err != nil {
+		return err
+	}
+	if reader.Len() != 0 {
+		return invalidRoaring("%d trailing bytes", reader.Len())
+	}
+	*r = *decoded
+	return nil
+}
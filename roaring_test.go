@@ -0,0 +1,414 @@
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRoaringBitmapMatchesHashSet(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 8))
+	rb := NewRoaringBitmap()
+	h := NewHashSet[uint32]()
+
+	// Mix sparse values across the whole range with a dense chunk that forces
+	// array containers to turn into bitmap containers and back.
+	for i := 0; i < 30000; i++ {
+		var v uint32
+		if i%2 == 0 {
+			v = r.Uint32()
+		} else {
+			v = 5<<16 | uint32(r.IntN(9000))
+		}
+		if r.IntN(4) == 0 {
+			rb.Remove(v)
+			h.Remove(v)
+		} else {
+			rb.Insert(v)
+			h.Insert(v)
+		}
+	}
+
+	if !rb.Equals(h) || !h.Equals(rb) || rb.Cardinality() != h.Cardinality() {
+		t.Fatal("RoaringBitmap diverged from hashSet")
+	}
+	if got := rb.ToSlice(); !slices.IsSorted(got) {
+		t.Error("ToSlice() is not in ascending order")
+	}
+
+	for v := range h.All() {
+		rb.Remove(v)
+	}
+	if !rb.IsEmpty() || len(rb.containers) != 0 {
+		t.Errorf("RoaringBitmap should be empty, has %d containers", len(rb.containers))
+	}
+}
+
+func TestRoaringBitmapContainerKinds(t *testing.T) {
+	var rb RoaringBitmap // the zero value is ready to use
+	for v := uint32(0); v < arrayContainerMax; v++ {
+		rb.Insert(v * 2)
+	}
+	if _, ok := rb.containers[0].(*arrayContainer); !ok {
+		t.Fatalf("container with %d values is %T, want array", arrayContainerMax, rb.containers[0])
+	}
+
+	rb.Insert(1)
+	if _, ok := rb.containers[0].(*bitmapContainer); !ok {
+		t.Fatalf("container with %d values is %T, want bitmap", arrayContainerMax+1, rb.containers[0])
+	}
+
+	rb.Remove(1)
+	if _, ok := rb.containers[0].(*arrayContainer); !ok {
+		t.Fatalf("container with %d values is %T, want array", arrayContainerMax, rb.containers[0])
+	}
+
+	// A long run of consecutive values is smallest as a run container.
+	dense := NewRoaringBitmap()
+	for v := uint32(100); v < 20000; v++ {
+		dense.Insert(1<<16 | v)
+	}
+	dense.Insert(7)
+	before := dense.ToSlice()
+	if !dense.RunOptimize() {
+		t.Error("RunOptimize() = false, want true")
+	}
+	if _, ok := dense.containers[1].(*runContainer); !ok {
+		t.Errorf("dense container is %T after RunOptimize, want run", dense.containers[1])
+	}
+	if _, ok := dense.containers[0].(*arrayContainer); !ok {
+		t.Errorf("singleton container is %T after RunOptimize, want array", dense.containers[0])
+	}
+	if !slices.Equal(dense.ToSlice(), before) {
+		t.Error("RunOptimize changed the contents of the bitmap")
+	}
+
+	// Fragmenting the runs makes other representations smaller again.
+	for v := uint32(100); v < 20000; v += 2 {
+		dense.Remove(1<<16 | v)
+	}
+	if dense.RunOptimize() {
+		t.Error("RunOptimize() = true for fragmented runs, want false")
+	}
+	if _, ok := dense.containers[1].(*bitmapContainer); !ok {
+		t.Errorf("fragmented container is %T after RunOptimize, want bitmap", dense.containers[1])
+	}
+}
+
+func TestRunContainer(t *testing.T) {
+	r := &runContainer{}
+	var c container = r
+	for _, v := range []uint16{5, 6, 7, 10, 9, 8, 0, 65535, 65534} {
+		c = c.add(v)
+	}
+	want := []interval16{{start: 0}, {start: 5, length: 5}, {start: 65534, length: 1}}
+	if !slices.Equal(r.runs, want) {
+		t.Fatalf("runs = %v, want %v", r.runs, want)
+	}
+	if c.cardinality() != 9 || c.numRuns() != 3 || !c.contains(65535) || c.contains(4) {
+		t.Errorf("unexpected run container state %v", r.runs)
+	}
+
+	for _, v := range []uint16{7, 5, 10, 0, 1} {
+		c = c.remove(v)
+	}
+	want = []interval16{{start: 6}, {start: 8, length: 1}, {start: 65534, length: 1}}
+	if !slices.Equal(r.runs, want) {
+		t.Fatalf("runs after removal = %v, want %v", r.runs, want)
+	}
+}
+
+func TestRoaringBitmapOperations(t *testing.T) {
+	// Build operands covering every pair of container kinds.
+	build := func(ranges ...[2]uint32) *RoaringBitmap {
+		rb := NewRoaringBitmap()
+		for _, rg := range ranges {
+			for v := rg[0]; v < rg[1]; v++ {
+				rb.Insert(v)
+			}
+		}
+		return rb
+	}
+	a := build([2]uint32{0, 10}, [2]uint32{1 << 16, 1<<16 + 6000}, [2]uint32{2 << 16, 2<<16 + 100}, [2]uint32{4 << 16, 4<<16 + 3})
+	b := build([2]uint32{5, 15}, [2]uint32{1<<16 + 3000, 1<<16 + 8000}, [2]uint32{3 << 16, 3<<16 + 50}, [2]uint32{4 << 16, 4<<16 + 3})
+	b.RunOptimize()
+
+	ha, hb := Collect(a.All()), Collect(b.All())
+	tests := []struct {
+		name     string
+		result   Set[uint32]
+		expected Set[uint32]
+	}{
+		{"Union", a.Union(b), ha.Union(hb)},
+		{"Intersection", a.Intersection(b), ha.Intersection(hb)},
+		{"Difference", a.Difference(b), ha.Difference(hb)},
+		{"SymmetricDifference", a.SymmetricDifference(b), ha.SymmetricDifference(hb)},
+		{"Reverse Difference", b.Difference(a), hb.Difference(ha)},
+		{"Union with mockSet", a.Union(newMockSet[uint32](1 << 31)), ha.Union(newMockSet[uint32](1 << 31))},
+		{"Intersection with mockSet", a.Intersection(newMockSet[uint32](3, 7)), newMockSet[uint32](3, 7)},
+		{"Difference with mockSet", a.Difference(hb), ha.Difference(hb)},
+		{"SymmetricDifference with mockSet", a.SymmetricDifference(hb), ha.SymmetricDifference(hb)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rb, ok := tt.result.(*RoaringBitmap)
+			if !ok {
+				t.Fatalf("%s returned %T, want *RoaringBitmap", tt.name, tt.result)
+			}
+			if !rb.Equals(tt.expected) {
+				t.Errorf("%s has %d elements, want %d", tt.name, rb.Cardinality(), tt.expected.Cardinality())
+			}
+			for _, c := range rb.containers {
+				if c.cardinality() == 0 {
+					t.Errorf("%s left an empty container", tt.name)
+				}
+			}
+		})
+	}
+
+	// Operations must not alias the containers of their operands.
+	u := a.Union(b).(*RoaringBitmap)
+	u.Insert(2<<16 + 5000)
+	u.Remove(4 << 16)
+	if a.Contains(2<<16+5000) || !a.Contains(4<<16) {
+		t.Error("modifying a union modified its operand")
+	}
+
+	inter := a.Intersection(b)
+	if !inter.IsProperSubsetOf(a) || !a.IsProperSupersetOf(inter) || !inter.IsSubsetOf(hb) || a.IsSubsetOf(b) {
+		t.Error("subset relations are incorrect")
+	}
+	if !a.Equals(ha) || a.Equals(b) || !b.IsSupersetOf(newMockSet[uint32](5)) {
+		t.Error("equality is incorrect")
+	}
+}
+
+func TestRoaringBitmapSerializationFormat(t *testing.T) {
+	le := binary.LittleEndian
+
+	t.Run("array containers", func(t *testing.T) {
+		rb := NewRoaringBitmap()
+		for _, v := range []uint32{1, 2, 1<<16 | 7} {
+			rb.Insert(v)
+		}
+
+		var want []byte
+		want = le.AppendUint32(want, serialCookieNoRuns)
+		want = le.AppendUint32(want, 2)       // containers
+		want = le.AppendUint16(want, 0)       // key
+		want = le.AppendUint16(want, 1)       // cardinality - 1
+		want = le.AppendUint16(want, 1)       // key
+		want = le.AppendUint16(want, 0)       // cardinality - 1
+		want = le.AppendUint32(want, 24)      // offset of the first container
+		want = le.AppendUint32(want, 28)      // offset of the second container
+		want = append(want, 1, 0, 2, 0, 7, 0) // values
+		checkSerialized(t, rb, want)
+	})
+
+	t.Run("run container", func(t *testing.T) {
+		rb := NewRoaringBitmap()
+		for v := uint32(10); v <= 20; v++ {
+			rb.Insert(v)
+		}
+		rb.RunOptimize()
+
+		var want []byte
+		want = le.AppendUint32(want, serialCookie) // one container, stored minus one
+		want = append(want, 0b1)                   // run flags
+		want = le.AppendUint16(want, 0)            // key
+		want = le.AppendUint16(want, 10)           // cardinality - 1
+		want = le.AppendUint16(want, 1)            // number of runs
+		want = le.AppendUint16(want, 10)           // run start
+		want = le.AppendUint16(want, 10)           // run length - 1
+		checkSerialized(t, rb, want)
+	})
+
+	t.Run("bitmap container", func(t *testing.T) {
+		rb := NewRoaringBitmap()
+		// One more value than fits in an array container.
+		for v := uint32(0); v <= 2*arrayContainerMax; v += 2 {
+			rb.Insert(3<<16 | v)
+		}
+
+		var want []byte
+		want = le.AppendUint32(want, serialCookieNoRuns)
+		want = le.AppendUint32(want, 1)
+		want = le.AppendUint16(want, 3)
+		want = le.AppendUint16(want, arrayContainerMax)
+		want = le.AppendUint32(want, 16)
+		for i := 0; i < bitmapContainerWords; i++ {
+			var word uint64
+			switch {
+			case i < 2*arrayContainerMax/64:
+				word = 0x5555555555555555
+			case i == 2*arrayContainerMax/64:
+				word = 1
+			}
+			want = le.AppendUint64(want, word)
+		}
+		checkSerialized(t, rb, want)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var want []byte
+		want = le.AppendUint32(want, serialCookieNoRuns)
+		want = le.AppendUint32(want, 0)
+		checkSerialized(t, NewRoaringBitmap(), want)
+	})
+}
+
+// TestRoaringBitmapReferenceVectors decodes the sample files of the Roaring
+// format specification, written by the Java reference implementation; see
+// testdata/README.md.
+func TestRoaringBitmapReferenceVectors(t *testing.T) {
+	want := NewRoaringBitmap()
+	for k := uint32(0); k < 100000; k += 1000 {
+		want.Insert(k)
+	}
+	for k := uint32(100000); k < 200000; k++ {
+		want.Insert(3 * k)
+	}
+	for k := uint32(700000); k < 800000; k++ {
+		want.Insert(k)
+	}
+
+	for _, name := range []string{"bitmapwithoutruns.bin", "bitmapwithruns.bin"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got RoaringBitmap
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if !got.Equals(want) {
+				t.Errorf("UnmarshalBinary() has %d elements, want %d", got.Cardinality(), want.Cardinality())
+			}
+		})
+	}
+
+	// The encodings of the reference match byte for byte, before and after
+	// converting containers to runs.
+	for _, name := range []string{"bitmapwithoutruns.bin", "bitmapwithruns.bin"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkSerialized(t, want, data)
+		want.RunOptimize()
+	}
+}
+
+// checkSerialized verifies that rb serializes to want and that want decodes
+// back to the same set.
+func checkSerialized(t *testing.T, rb *RoaringBitmap, want []byte) {
+	t.Helper()
+	got, err := rb.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("MarshalBinary() = %v, want %v", got, want)
+	}
+
+	var decoded RoaringBitmap
+	if err := decoded.UnmarshalBinary(want); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if !decoded.Equals(rb) {
+		t.Errorf("decoded %v, want %v", &decoded, rb)
+	}
+}
+
+func TestRoaringBitmapRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewPCG(9, 10))
+	rb := NewRoaringBitmap()
+	for i := 0; i < 10000; i++ {
+		rb.Insert(r.Uint32() & 0x000FFFFF)
+	}
+	for v := uint32(1 << 24); v < 1<<24+70000; v++ {
+		rb.Insert(v)
+	}
+
+	for _, optimize := range []bool{false, true} {
+		if optimize {
+			rb.RunOptimize()
+		}
+
+		var buf bytes.Buffer
+		written, err := rb.WriteTo(&buf)
+		if err != nil {
+			t.Fatalf("WriteTo() error = %v", err)
+		}
+		if written != int64(buf.Len()) {
+			t.Errorf("WriteTo() = %d, wrote %d bytes", written, buf.Len())
+		}
+
+		decoded := NewRoaringBitmap()
+		read, err := decoded.ReadFrom(&buf)
+		if err != nil {
+			t.Fatalf("ReadFrom() error = %v", err)
+		}
+		if read != written {
+			t.Errorf("ReadFrom() = %d, want %d", read, written)
+		}
+		if !decoded.Equals(rb) {
+			t.Errorf("round trip with RunOptimize=%v lost elements", optimize)
+		}
+	}
+}
+
+func TestRoaringBitmapInvalidInput(t *testing.T) {
+	le := binary.LittleEndian
+	valid, _ := func() *RoaringBitmap {
+		rb := NewRoaringBitmap()
+		rb.Insert(1)
+		rb.Insert(2)
+		return rb
+	}().MarshalBinary()
+
+	header := func(cookie uint32, rest ...uint16) []byte {
+		data := le.AppendUint32(nil, cookie)
+		for _, v := range rest {
+			data = le.AppendUint16(data, v)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty input", nil},
+		{"unknown cookie", header(42)},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing bytes", append(slices.Clone(valid), 0)},
+		{"too many containers", le.AppendUint32(le.AppendUint32(nil, serialCookieNoRuns), 1<<16+1)},
+		{"unsorted array", append(header(serialCookieNoRuns, 1, 0, 0, 1, 8, 0), 2, 0, 1, 0)},
+		{"unsorted keys", header(serialCookieNoRuns, 2, 0, 5, 0, 3, 0, 0, 0, 0, 0, 0, 0, 1, 0, 1)},
+		{"empty run container", append(header(serialCookie), 1, 0, 0, 0, 0, 0, 0)},
+		{"overflowing run", append(header(serialCookie), 1, 0, 0, 1, 0, 1, 0, 0xFF, 0xFF, 1, 0)},
+		{"cardinality mismatch", append(header(serialCookie), 1, 0, 0, 5, 0, 1, 0, 0, 0, 1, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rb := NewRoaringBitmap()
+			rb.Insert(99)
+			err := rb.UnmarshalBinary(tt.data)
+			if !errors.Is(err, ErrInvalidRoaringFormat) {
+				t.Errorf("UnmarshalBinary() error = %v, want ErrInvalidRoaringFormat", err)
+			}
+			if !rb.Contains(99) || rb.Cardinality() != 1 {
+				t.Error("failed UnmarshalBinary modified the bitmap")
+			}
+		})
+	}
+}
@@ -1,13 +1,37 @@
 // Package set provides a generic set data interface and implementations of this interface.
 //
 // The interface is based on the mathematical definition of a set and provides operations like
-// union, intersection, difference, and subset relationships. The package also provides a map-based
-// in-memory implementation of the Set interface called hashSet.
+// union, intersection, difference, and subset relationships. The package provides several
+// implementations of the Set interface:
 //
-// The CartesianProduct and PowerSet functions are also provided separately to avoid type
+//   - NewHashSet returns a map-based in-memory implementation called hashSet.
+//   - ConcurrentSet is guarded by a single read-write mutex and is safe for concurrent use.
+//   - NewShardedSet returns a concurrent set split into independently locked shards, suited
+//     to write-heavy workloads.
+//   - SortedSet keeps elements of ordered types in ascending order and supports order queries.
+//   - LinkedHashSet remembers insertion order, so iteration and String are stable.
+//   - BitSet stores small non-negative integers in a dense bitmap.
+//   - RoaringBitmap stores sparse 32-bit integers in compressed Roaring bitmaps.
+//   - PersistentSet is an immutable set whose versions share structure in a hash array mapped trie.
+//
+// The CartesianProduct and PowerSet functions, and combinatorial generators such as
+// Combinations, Permutations and Partitions, are also provided separately to avoid type
 // dependency cycles while still maintaining the complete set of operations from set theory.
+// Sets of sets are built from FrozenSet, an immutable set whose values are equal whenever
+// their elements are, so that PowerSet and other nested sets compare subsets by content.
+// Multiset counts how many copies of each element it holds, for when membership alone is
+// not enough. DisjointSet partitions elements into equivalence classes with union-find, and
+// Relation treats a set of pairs as a binary relation with its properties and closures.
+//
+// Generic functions such as Map, Filter, Reduce, Partition and GroupBy transform any Set,
+// returning sets of the same implementation as their argument where possible.
+//
+// Render, RenderProduct and RenderProductBuilder write sets in Unicode, LaTeX or MathML
+// notation, for use in teaching material alongside the code.
 package set
 
+import "iter"
+
 // Set is a generic interface that defines the operations that can be performed on a set.
 // A set is defined as an unordered collection of unique, arbitrary elements.
 // The zero value of a set is an empty set.
@@ -20,6 +44,29 @@ type Set[T comparable] interface {
 	// If the element does not exist, the set remains unchanged.
 	Remove(elem T)
 
+	// InsertAll adds the elements to the set and returns the number of elements
+	// that were not already present.
+	InsertAll(elems ...T) int
+
+	// RemoveAll deletes the elements from the set and returns the number of
+	// elements that were present.
+	RemoveAll(elems ...T) int
+
+	// RetainIf deletes every element for which keep returns false and returns
+	// the number of elements deleted.
+	RetainIf(keep func(T) bool) int
+
+	// RemoveIf deletes every element for which remove returns true and returns
+	// the number of elements deleted.
+	RemoveIf(remove func(T) bool) int
+
+	// Clear deletes all elements from the set and returns how many there were.
+	Clear() int
+
+	// Pop deletes an arbitrary element from the set and returns it.
+	// The boolean is false if the set is empty.
+	Pop() (T, bool)
+
 	// Contains reports whether the element exists in the set.
 	Contains(elem T) bool
 
@@ -64,11 +111,173 @@ type Set[T comparable] interface {
 	// or the other set, but not in both (**X** Δ **Y**).
 	SymmetricDifference(other Set[T]) Set[T]
 
+	// UnionWith adds the elements of the other set to this set in place
+	// (**X** ← **X** ∪ **Y**) and returns the number of elements added.
+	UnionWith(other Set[T]) int
+
+	// IntersectWith deletes the elements of this set that are not in the other set
+	// (**X** ← **X** ∩ **Y**) and returns the number of elements deleted.
+	IntersectWith(other Set[T]) int
+
+	// DifferenceWith deletes the elements of this set that are in the other set
+	// (**X** ← **X** \ **Y**) and returns the number of elements deleted.
+	DifferenceWith(other Set[T]) int
+
+	// SymmetricDifferenceWith deletes the elements of this set that are in the other set
+	// and adds those that are not (**X** ← **X** Δ **Y**). It returns the number of elements
+	// added or deleted, which is the cardinality of the other set.
+	SymmetricDifferenceWith(other Set[T]) int
+
+	// All returns an iterator over the elements of the set.
+	// Unlike ToSlice, it does not copy the elements before yielding them.
+	// **Note**: The order of elements is not guaranteed to be stable between calls.
+	All() iter.Seq[T]
+
 	// ToSlice returns a slice containing all elements in the set.
 	// **Note**: The order of elements is not guaranteed to be stable between calls.
 	ToSlice() []T
 
-	// String returns a string representation of the set.
-	// For sets of strings, the elements are sorted lexicographically.
+	// String returns a string representation of the set, such as {1, 2, 3}.
+	// Implementations without an order of their own sort the elements, so that
+	// equal sets print the same.
 	String() string
 }
+
+// The helpers below implement the set algebra purely in terms of the Set
+// interface. Implementations without a faster native strategy delegate to them,
+// passing an empty set of their own kind as the destination.
+
+// isSubset reports whether every element of a is also an element of b.
+func isSubset[T comparable](a, b Set[T]) bool {
+	if a.Cardinality() > b.Cardinality() {
+		return false
+	}
+	for elem := range a.All() {
+		if !b.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// equal reports whether a and b contain exactly the same elements.
+func equal[T comparable](a, b Set[T]) bool {
+	return a.Cardinality() == b.Cardinality() && isSubset(a, b)
+}
+
+// unionInto inserts the elements of a and b into dst and returns dst.
+func unionInto[T comparable](dst, a, b Set[T]) Set[T] {
+	Insert(dst, a.All())
+	Insert(dst, b.All())
+	return dst
+}
+
+// intersectionInto inserts the elements common to a and b into dst and returns
+// dst. It iterates over the smaller of the two sets.
+func intersectionInto[T comparable](dst, a, b Set[T]) Set[T] {
+	if a.Cardinality() > b.Cardinality() {
+		a, b = b, a
+	}
+	for elem := range a.All() {
+		if b.Contains(elem) {
+			dst.Insert(elem)
+		}
+	}
+	return dst
+}
+
+// differenceInto inserts the elements of a that are not in b into dst and
+// returns dst.
+func differenceInto[T comparable](dst, a, b Set[T]) Set[T] {
+	for elem := range a.All() {
+		if !b.Contains(elem) {
+			dst.Insert(elem)
+		}
+	}
+	return dst
+}
+
+// symmetricDifferenceInto inserts the elements that are in exactly one of a
+// and b into dst and returns dst.
+func symmetricDifferenceInto[T comparable](dst, a, b Set[T]) Set[T] {
+	differenceInto(dst, a, b)
+	return differenceInto(dst, b, a)
+}
+
+// The helpers below implement the in-place bulk operations in terms of the Set
+// interface. They never delete from a set while iterating over it, so they are
+// safe for every implementation, including when other is the set itself.
+
+// insertAll inserts the elements yielded by seq into s and returns the number
+// of elements added.
+func insertAll[T comparable](s Set[T], seq iter.Seq[T]) int {
+	before := s.Cardinality()
+	Insert(s, seq)
+	return s.Cardinality() - before
+}
+
+// removeAll removes the elements yielded by seq from s and returns the number
+// of elements removed.
+func removeAll[T comparable](s Set[T], seq iter.Seq[T]) int {
+	before := s.Cardinality()
+	for elem := range seq {
+		s.Remove(elem)
+	}
+	return before - s.Cardinality()
+}
+
+// removeIf removes the elements of s for which remove returns true and returns
+// how many there were. The elements are collected before any is removed.
+func removeIf[T comparable](s Set[T], remove func(T) bool) int {
+	var doomed []T
+	for elem := range s.All() {
+		if remove(elem) {
+			doomed = append(doomed, elem)
+		}
+	}
+	for _, elem := range doomed {
+		s.Remove(elem)
+	}
+	return len(doomed)
+}
+
+// retainIf removes the elements of s for which keep returns false and returns
+// how many there were.
+func retainIf[T comparable](s Set[T], keep func(T) bool) int {
+	return removeIf(s, func(elem T) bool { return !keep(elem) })
+}
+
+// differenceWith removes the elements of other from s and returns the number
+// of elements removed. It iterates over the smaller of the two sets.
+func differenceWith[T comparable](s, other Set[T]) int {
+	if other.Cardinality() < s.Cardinality() {
+		return removeAll(s, other.All())
+	}
+	return removeIf(s, other.Contains)
+}
+
+// symmetricDifferenceWith toggles the membership in s of every element of
+// other and returns the number of elements toggled.
+func symmetricDifferenceWith[T comparable](s, other Set[T]) int {
+	elems := other.ToSlice()
+	for _, elem := range elems {
+		if s.Contains(elem) {
+			s.Remove(elem)
+		} else {
+			s.Insert(elem)
+		}
+	}
+	return len(elems)
+}
+
+// pop removes the first element yielded by s.All and returns it.
+func pop[T comparable](s Set[T]) (elem T, ok bool) {
+	for elem = range s.All() {
+		ok = true
+		break
+	}
+	if ok {
+		s.Remove(elem)
+	}
+	return elem, ok
+}
@@ -1,8 +1,14 @@
 package set
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"iter"
+	"maps"
 	"math"
+	"runtime"
+	"slices"
 	"testing"
 	"time"
 )
@@ -795,7 +801,10 @@ func TestPowerSet(t *testing.T) {
 			}
 
 			// Get power set
-			powerSet := PowerSet(input)
+			powerSet, err := PowerSet(input)
+			if err != nil {
+				t.Fatalf("PowerSet() error = %v", err)
+			}
 
 			// Check size
 			if powerSet.Cardinality() != tt.expectedSize {
@@ -804,142 +813,454 @@ func TestPowerSet(t *testing.T) {
 
 			// Check expected elements
 			for _, expectedElements := range tt.checkElements {
-				found := false
 				expectedSet := NewHashSet[int]()
 				for _, elem := range expectedElements {
 					expectedSet.Insert(elem)
 				}
 
-				for _, subset := range powerSet.ToSlice() {
-					if subset.Equals(expectedSet) {
-						found = true
-						break
-					}
+				if !powerSet.Contains(Freeze(expectedSet)) {
+					t.Errorf("PowerSet() missing expected subset %v", expectedElements)
 				}
+			}
 
-				if !found {
-					t.Errorf("PowerSet() missing expected subset %v", expectedElements)
+			// Inserting an equal subset again must not add a duplicate
+			powerSet.Insert(NewFrozenSet(tt.elements...))
+			if powerSet.Cardinality() != tt.expectedSize {
+				t.Errorf("PowerSet() size after re-inserting S = %v, want %v", powerSet.Cardinality(), tt.expectedSize)
+			}
+		})
+	}
+}
+
+func TestPowerSetLimit(t *testing.T) {
+	input := NewHashSet[int]()
+	for i := range DefaultPowerSetLimit + 1 {
+		input.Insert(i)
+	}
+
+	if _, err := PowerSet(input); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("PowerSet() of %d elements error = %v, want ErrTooLarge", input.Cardinality(), err)
+	}
+	if _, err := PowerSetWithLimit(input, 3); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("PowerSetWithLimit(3) error = %v, want ErrTooLarge", err)
+	}
+
+	input = newMockSet(1, 2, 3)
+	powerSet, err := PowerSetWithLimit(input, 3)
+	if err != nil || powerSet.Cardinality() != 8 {
+		t.Errorf("PowerSetWithLimit(3) of 3 elements = %v, %v, want 8 subsets", powerSet, err)
+	}
+}
+
+func TestPowerSetSeq(t *testing.T) {
+	input := newMockSet(1, 2, 3)
+
+	// Every subset is yielded once, in binary counting order of the elements.
+	elems := input.ToSlice()
+	i := 0
+	for subset := range PowerSetSeq(context.Background(), input) {
+		expected := NewHashSet[int]()
+		for j, elem := range elems {
+			if i&(1<<j) != 0 {
+				expected.Insert(elem)
+			}
+		}
+		if !subset.Equals(expected) {
+			t.Errorf("subset %d = %v, want %v", i, subset, expected)
+		}
+		i++
+	}
+	if i != 8 {
+		t.Errorf("PowerSetSeq() yielded %d subsets, want 8", i)
+	}
+
+	// Subsets are independent sets owned by the caller.
+	var first Set[int]
+	for subset := range PowerSetSeq(context.Background(), input) {
+		if first == nil {
+			first = subset
+			first.Insert(42)
+		} else if subset.Contains(42) {
+			t.Error("modifying a yielded subset affected later subsets")
+		}
+	}
+
+	// Iterating a power set far too large to build stops when asked to.
+	large := NewHashSet[int]()
+	for i := range 100 {
+		large.Insert(i)
+	}
+	count := 0
+	for range PowerSetSeq(context.Background(), large) {
+		count++
+		if count == 1000 {
+			break
+		}
+	}
+	if count != 1000 {
+		t.Errorf("PowerSetSeq() of 100 elements yielded %d subsets, want 1000", count)
+	}
+}
+
+func TestPowerSetSeqCancellation(t *testing.T) {
+	large := NewHashSet[int]()
+	for i := range 64 {
+		large.Insert(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	count := 0
+	for range PowerSetSeq(ctx, large) {
+		count++
+		if count == 10 {
+			cancel()
+		}
+	}
+	if count != 10 || ctx.Err() == nil {
+		t.Errorf("PowerSetSeq() yielded %d subsets after cancellation at 10", count)
+	}
+
+	count = 0
+	for range PowerSetSeqOfSize(ctx, large, 2) {
+		count++
+	}
+	if count != 0 {
+		t.Errorf("PowerSetSeqOfSize() with a cancelled context yielded %d subsets", count)
+	}
+}
+
+func TestPowerSetSeqOfSize(t *testing.T) {
+	input := newMockSet(1, 2, 3, 4, 5)
+	tests := []struct {
+		k        int
+		expected int
+	}{
+		{-1, 0},
+		{0, 1},
+		{1, 5},
+		{2, 10},
+		{3, 10},
+		{5, 1},
+		{6, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("k=%d", tt.k), func(t *testing.T) {
+			seen := NewHashSet[FrozenSet[int]]()
+			for subset := range PowerSetSeqOfSize(context.Background(), input, tt.k) {
+				if subset.Cardinality() != tt.k || !subset.IsSubsetOf(input) {
+					t.Errorf("PowerSetSeqOfSize(%d) yielded %v", tt.k, subset)
 				}
+				frozen := Freeze(subset)
+				if seen.Contains(frozen) {
+					t.Errorf("PowerSetSeqOfSize(%d) yielded %v twice", tt.k, subset)
+				}
+				seen.Insert(frozen)
+			}
+			if seen.Cardinality() != tt.expected {
+				t.Errorf("PowerSetSeqOfSize(%d) yielded %d subsets, want %d", tt.k, seen.Cardinality(), tt.expected)
 			}
 		})
 	}
 }
 
-func TestTypeAssertionPanics(t *testing.T) {
+// mockSet is a minimal slice-backed implementation of the Set interface used to
+// check that operations work between different implementations.
+type mockSet[T comparable] struct {
+	elems []T
+}
+
+func newMockSet[T comparable](elems ...T) *mockSet[T] {
+	m := &mockSet[T]{}
+	for _, elem := range elems {
+		m.Insert(elem)
+	}
+	return m
+}
+
+func (m *mockSet[T]) Insert(elem T) {
+	if !m.Contains(elem) {
+		m.elems = append(m.elems, elem)
+	}
+}
+
+func (m *mockSet[T]) Remove(elem T) {
+	for i, e := range m.elems {
+		if e == elem {
+			m.elems = append(m.elems[:i], m.elems[i+1:]...)
+			return
+		}
+	}
+}
+
+func (m *mockSet[T]) Contains(elem T) bool {
+	for _, e := range m.elems {
+		if e == elem {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockSet[T]) Cardinality() int { return len(m.elems) }
+func (m *mockSet[T]) IsEmpty() bool    { return len(m.elems) == 0 }
+
+func (m *mockSet[T]) Equals(other Set[T]) bool {
+	return m.Cardinality() == other.Cardinality() && m.IsSubsetOf(other)
+}
+
+func (m *mockSet[T]) IsSubsetOf(other Set[T]) bool {
+	for _, e := range m.elems {
+		if !other.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mockSet[T]) IsSupersetOf(other Set[T]) bool {
+	for e := range other.All() {
+		if !m.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mockSet[T]) IsProperSubsetOf(other Set[T]) bool {
+	return m.Cardinality() < other.Cardinality() && m.IsSubsetOf(other)
+}
+
+func (m *mockSet[T]) IsProperSupersetOf(other Set[T]) bool {
+	return m.Cardinality() > other.Cardinality() && m.IsSupersetOf(other)
+}
+
+func (m *mockSet[T]) Union(other Set[T]) Set[T] {
+	result := newMockSet(m.elems...)
+	for e := range other.All() {
+		result.Insert(e)
+	}
+	return result
+}
+
+func (m *mockSet[T]) Intersection(other Set[T]) Set[T] {
+	result := newMockSet[T]()
+	for _, e := range m.elems {
+		if other.Contains(e) {
+			result.Insert(e)
+		}
+	}
+	return result
+}
+
+func (m *mockSet[T]) Difference(other Set[T]) Set[T] {
+	result := newMockSet[T]()
+	for _, e := range m.elems {
+		if !other.Contains(e) {
+			result.Insert(e)
+		}
+	}
+	return result
+}
+
+func (m *mockSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return m.Difference(other).Union(other.Difference(m))
+}
+
+func (m *mockSet[T]) All() iter.Seq[T] {
+	return slices.Values(m.elems)
+}
+
+func (m *mockSet[T]) ToSlice() []T {
+	result := make([]T, len(m.elems))
+	copy(result, m.elems)
+	return result
+}
+
+func (m *mockSet[T]) String() string { return fmt.Sprint(m.elems) }
+
+func (m *mockSet[T]) InsertAll(elems ...T) int             { return insertAll(m, slices.Values(elems)) }
+func (m *mockSet[T]) RemoveAll(elems ...T) int             { return removeAll(m, slices.Values(elems)) }
+func (m *mockSet[T]) RetainIf(keep func(T) bool) int       { return retainIf(m, keep) }
+func (m *mockSet[T]) RemoveIf(remove func(T) bool) int     { return removeIf(m, remove) }
+func (m *mockSet[T]) Pop() (T, bool)                       { return pop(m) }
+func (m *mockSet[T]) UnionWith(other Set[T]) int           { return insertAll(m, other.All()) }
+func (m *mockSet[T]) IntersectWith(other Set[T]) int       { return retainIf(m, other.Contains) }
+func (m *mockSet[T]) DifferenceWith(other Set[T]) int      { return differenceWith(m, other) }
+func (m *mockSet[T]) SymmetricDifferenceWith(o Set[T]) int { return symmetricDifferenceWith(m, o) }
+
+func (m *mockSet[T]) Clear() int {
+	n := len(m.elems)
+	m.elems = nil
+	return n
+}
+
+func TestCrossImplementationRelations(t *testing.T) {
 	tests := []struct {
-		name string
-		fn   func()
+		name          string
+		hashElements  []int
+		mockElements  []int
+		equals        bool
+		isSubset      bool
+		isSuperset    bool
+		isProperSub   bool
+		isProperSuper bool
 	}{
 		{
-			name: "Equals with wrong type",
-			fn: func() {
-				s1 := NewHashSet[int]()
-				var s2 Set[int] = &mockSet[int]{} // Different implementation
-				s1.Equals(s2)
-			},
+			name:       "both empty",
+			equals:     true,
+			isSubset:   true,
+			isSuperset: true,
 		},
 		{
-			name: "IsSubsetOf with wrong type",
-			fn: func() {
-				s1 := NewHashSet[int]()
-				var s2 Set[int] = &mockSet[int]{}
-				s1.IsSubsetOf(s2)
-			},
+			name:         "equal sets",
+			hashElements: []int{1, 2, 3},
+			mockElements: []int{3, 2, 1},
+			equals:       true,
+			isSubset:     true,
+			isSuperset:   true,
 		},
 		{
-			name: "IsSupersetOf with wrong type",
-			fn: func() {
-				s1 := NewHashSet[int]()
-				var s2 Set[int] = &mockSet[int]{}
-				s1.IsSupersetOf(s2)
-			},
+			name:         "proper subset",
+			hashElements: []int{1, 2},
+			mockElements: []int{1, 2, 3},
+			isSubset:     true,
+			isProperSub:  true,
+		},
+		{
+			name:          "proper superset",
+			hashElements:  []int{1, 2, 3},
+			mockElements:  []int{2, 3},
+			isSuperset:    true,
+			isProperSuper: true,
+		},
+		{
+			name:         "same size but different",
+			hashElements: []int{1, 2},
+			mockElements: []int{2, 3},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r == nil {
-					t.Errorf("%s should panic", tt.name)
-				}
-			}()
-			tt.fn()
+			h := NewHashSet[int]()
+			for _, elem := range tt.hashElements {
+				h.Insert(elem)
+			}
+			m := newMockSet(tt.mockElements...)
+
+			if got := h.Equals(m); got != tt.equals {
+				t.Errorf("Equals() = %v, want %v", got, tt.equals)
+			}
+			if got := h.IsSubsetOf(m); got != tt.isSubset {
+				t.Errorf("IsSubsetOf() = %v, want %v", got, tt.isSubset)
+			}
+			if got := h.IsSupersetOf(m); got != tt.isSuperset {
+				t.Errorf("IsSupersetOf() = %v, want %v", got, tt.isSuperset)
+			}
+			if got := h.IsProperSubsetOf(m); got != tt.isProperSub {
+				t.Errorf("IsProperSubsetOf() = %v, want %v", got, tt.isProperSub)
+			}
+			if got := h.IsProperSupersetOf(m); got != tt.isProperSuper {
+				t.Errorf("IsProperSupersetOf() = %v, want %v", got, tt.isProperSuper)
+			}
+
+			// The relations must agree when the receiver is the other implementation.
+			if got := m.Equals(h); got != tt.equals {
+				t.Errorf("mockSet.Equals() = %v, want %v", got, tt.equals)
+			}
+			if got := m.IsSupersetOf(h); got != tt.isSubset {
+				t.Errorf("mockSet.IsSupersetOf() = %v, want %v", got, tt.isSubset)
+			}
 		})
 	}
 }
 
-// mockSet implements Set interface for testing type assertion panics
-type mockSet[T comparable] struct{}
-
-func (m *mockSet[T]) Insert(_ T)                          {}
-func (m *mockSet[T]) Remove(_ T)                          {}
-func (m *mockSet[T]) Contains(_ T) bool                   { return false }
-func (m *mockSet[T]) Cardinality() int                    { return 0 }
-func (m *mockSet[T]) IsEmpty() bool                       { return true }
-func (m *mockSet[T]) Equals(_ Set[T]) bool                { return false }
-func (m *mockSet[T]) IsSubsetOf(_ Set[T]) bool            { return false }
-func (m *mockSet[T]) IsSupersetOf(_ Set[T]) bool          { return false }
-func (m *mockSet[T]) IsProperSubsetOf(_ Set[T]) bool      { return false }
-func (m *mockSet[T]) IsProperSupersetOf(_ Set[T]) bool    { return false }
-func (m *mockSet[T]) Union(_ Set[T]) Set[T]               { return nil }
-func (m *mockSet[T]) Intersection(_ Set[T]) Set[T]        { return nil }
-func (m *mockSet[T]) Difference(_ Set[T]) Set[T]          { return nil }
-func (m *mockSet[T]) SymmetricDifference(_ Set[T]) Set[T] { return nil }
-func (m *mockSet[T]) ToSlice() []T                        { return nil }
-func (m *mockSet[T]) String() string                      { return "" }
-
-// Test all operation panics with invalid set types
-func TestOperationPanics(t *testing.T) {
+func TestCrossImplementationOperations(t *testing.T) {
 	tests := []struct {
-		name string
-		fn   func()
+		name         string
+		op           func(a, b Set[int]) Set[int]
+		hashElements []int
+		mockElements []int
+		expected     []int
 	}{
 		{
-			name: "Union with wrong type",
-			fn: func() {
-				s1 := NewHashSet[int]()
-				var s2 Set[int] = &mockSet[int]{}
-				s1.Union(s2)
-			},
+			name:         "Union",
+			op:           Set[int].Union,
+			hashElements: []int{1, 2, 3},
+			mockElements: []int{3, 4},
+			expected:     []int{1, 2, 3, 4},
 		},
 		{
-			name: "Intersection with wrong type",
-			fn: func() {
-				s1 := NewHashSet[int]()
-				var s2 Set[int] = &mockSet[int]{}
-				s1.Intersection(s2)
-			},
+			name:         "Intersection with smaller receiver",
+			op:           Set[int].Intersection,
+			hashElements: []int{2, 3},
+			mockElements: []int{1, 2, 3, 4},
+			expected:     []int{2, 3},
 		},
 		{
-			name: "Difference with wrong type",
-			fn: func() {
-				s1 := NewHashSet[int]()
-				var s2 Set[int] = &mockSet[int]{}
-				s1.Difference(s2)
-			},
+			name:         "Intersection with larger receiver",
+			op:           Set[int].Intersection,
+			hashElements: []int{1, 2, 3, 4},
+			mockElements: []int{3, 4, 5},
+			expected:     []int{3, 4},
 		},
 		{
-			name: "SymmetricDifference with wrong type",
-			fn: func() {
-				s1 := NewHashSet[int]()
-				var s2 Set[int] = &mockSet[int]{}
-				s1.SymmetricDifference(s2)
-			},
+			name:         "Difference",
+			op:           Set[int].Difference,
+			hashElements: []int{1, 2, 3},
+			mockElements: []int{2, 5},
+			expected:     []int{1, 3},
+		},
+		{
+			name:         "SymmetricDifference",
+			op:           Set[int].SymmetricDifference,
+			hashElements: []int{1, 2, 3},
+			mockElements: []int{2, 3, 4},
+			expected:     []int{1, 4},
+		},
+		{
+			name:         "Union with empty mock",
+			op:           Set[int].Union,
+			hashElements: []int{1},
+			expected:     []int{1},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			defer func() {
-				if r := recover(); r == nil {
-					t.Errorf("%s should panic", tt.name)
-				}
-			}()
-			tt.fn()
+			h := NewHashSet[int]()
+			for _, elem := range tt.hashElements {
+				h.Insert(elem)
+			}
+			m := newMockSet(tt.mockElements...)
+			expected := newMockSet(tt.expected...)
+
+			result := tt.op(h, m)
+			if !result.Equals(expected) {
+				t.Errorf("%s = %v, want %v", tt.name, result, expected)
+			}
+			if _, ok := result.(*hashSet[int]); !ok {
+				t.Errorf("%s returned %T, want *hashSet[int]", tt.name, result)
+			}
 		})
 	}
 }
 
+func TestCrossImplementationDerivedOperations(t *testing.T) {
+	m := newMockSet(1, 2)
+
+	product := CartesianProduct[int](NewHashSet[int](), m)
+	if !product.IsEmpty() {
+		t.Errorf("CartesianProduct with empty hashSet = %v, want empty", product)
+	}
+
+	if powerSet, err := PowerSet[int](m); err != nil || powerSet.Cardinality() != 4 {
+		t.Errorf("PowerSet(mockSet) = %v, %v, want 4 subsets", powerSet, err)
+	}
+}
+
 // Test boundary cases for Contains and Remove
 func TestContainsAndRemoveBoundary(t *testing.T) {
 	set := NewHashSet[int]()
@@ -1031,6 +1352,130 @@ func TestCartesianProductExtended(t *testing.T) {
 	}
 }
 
+// oversizedSet reports more elements than it yields, as a set computed on
+// demand might.
+type oversizedSet struct {
+	*mockSet[int]
+	cardinality int
+}
+
+func (s oversizedSet) Cardinality() int { return s.cardinality }
+
+func TestCartesianProductSizeHint(t *testing.T) {
+	// The product of the cardinalities overflows, so it must not be used as
+	// an allocation size.
+	a := oversizedSet{newMockSet(1), math.MaxInt}
+	if got := CartesianProduct[int](a, newMockSet(2, 3)); !got.Equals(newMockSet(Pair[int]{1, 2}, Pair[int]{1, 3})) {
+		t.Errorf("CartesianProduct() = %v, want {(1, 2), (1, 3)}", got)
+	}
+	if got := CartesianProduct2[int, string](a, newMockSet("x")); !got.Equals(newMockSet(Pair2[int, string]{1, "x"})) {
+		t.Errorf("CartesianProduct2() = %v, want {(1, x)}", got)
+	}
+
+	// A large product is not allocated before its pairs are built.
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	CartesianProduct[int](oversizedSet{newMockSet(1), 1 << 22}, newMockSet(2, 3))
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16<<20 {
+		t.Errorf("CartesianProduct() of 2 pairs allocated %d bytes", allocated)
+	}
+}
+
+func TestCartesianProductHeterogeneous(t *testing.T) {
+	users := newMockSet("ann", "bob")
+	roles := NewHashSet[int]()
+	roles.Insert(1)
+	roles.Insert(2)
+	roles.Insert(3)
+
+	grants := CartesianProduct2[string, int](users, roles)
+	if grants.Cardinality() != 6 {
+		t.Errorf("CartesianProduct2() size = %d, want 6", grants.Cardinality())
+	}
+	if !grants.Contains(Pair2[string, int]{First: "bob", Second: 3}) {
+		t.Error("CartesianProduct2() missing pair (bob, 3)")
+	}
+	if got := (Pair2[string, int]{First: "ann", Second: 1}).String(); got != "(ann, 1)" {
+		t.Errorf("Pair2.String() = %q, want %q", got, "(ann, 1)")
+	}
+
+	// The lazy form can be filtered and stopped without building A × B.
+	admins := NewHashSet[Pair2[string, int]]()
+	for p := range CartesianProductSeq[string, int](users, roles) {
+		if p.Second == 1 {
+			admins.Insert(p)
+		}
+	}
+	if admins.Cardinality() != 2 || !admins.IsSubsetOf(grants) {
+		t.Errorf("filtered CartesianProductSeq() = %v", admins)
+	}
+	count := 0
+	for range CartesianProductSeq[string, int](users, roles) {
+		count++
+		if count == 4 {
+			break
+		}
+	}
+	if count != 4 {
+		t.Errorf("CartesianProductSeq() yielded %d pairs after break at 4", count)
+	}
+
+	flags := newMockSet(true)
+	triples := CartesianProduct3[string, int, bool](users, roles, flags)
+	if triples.Cardinality() != 6 || !triples.Contains(Triple[string, int, bool]{"ann", 2, true}) {
+		t.Errorf("CartesianProduct3() = %v", triples)
+	}
+	if got := (Triple[string, int, bool]{"ann", 2, true}).String(); got != "(ann, 2, true)" {
+		t.Errorf("Triple.String() = %q, want %q", got, "(ann, 2, true)")
+	}
+}
+
+func TestCartesianProductN(t *testing.T) {
+	a, b, c := newMockSet(1, 2), newMockSet(3), newMockSet(4, 5, 6)
+
+	tests := []struct {
+		name  string
+		sets  []Set[int]
+		count int
+	}{
+		{"no sets", nil, 1},
+		{"one set", []Set[int]{a}, 2},
+		{"three sets", []Set[int]{a, b, c}, 6},
+		{"with empty set", []Set[int]{a, NewHashSet[int](), c}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := NewHashSet[string]()
+			for tuple := range CartesianProductN(tt.sets...) {
+				if len(tuple) != len(tt.sets) {
+					t.Fatalf("tuple %v has arity %d, want %d", tuple, len(tuple), len(tt.sets))
+				}
+				for i, elem := range tuple {
+					if !tt.sets[i].Contains(elem) {
+						t.Errorf("tuple %v element %d is not in set %d", tuple, i, i)
+					}
+				}
+				seen.Insert(fmt.Sprint(tuple))
+			}
+			if seen.Cardinality() != tt.count {
+				t.Errorf("CartesianProductN() yielded %d distinct tuples, want %d", seen.Cardinality(), tt.count)
+			}
+		})
+	}
+
+	// Tuples are lexicographic in the positions of the elements of each set.
+	var got [][]int
+	for tuple := range CartesianProductN[int](newMockSet(1, 2), newMockSet(3, 4)) {
+		got = append(got, tuple)
+	}
+	want := [][]int{{1, 3}, {1, 4}, {2, 3}, {2, 4}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("CartesianProductN() = %v, want %v", got, want)
+	}
+}
+
 // Test Set operations with mixed element types
 func TestMixedTypeOperations(t *testing.T) {
 	set := NewHashSet[interface{}]()
@@ -1065,3 +1510,175 @@ func TestMixedTypeOperations(t *testing.T) {
 		t.Errorf("Cardinality = %d, want 1", set.Cardinality())
 	}
 }
+
+func TestAll(t *testing.T) {
+	s := NewHashSet[int]()
+	for i := 0; i < 10; i++ {
+		s.Insert(i)
+	}
+
+	seen := NewHashSet[int]()
+	for elem := range s.All() {
+		if seen.Contains(elem) {
+			t.Errorf("All() yielded %d more than once", elem)
+		}
+		seen.Insert(elem)
+	}
+	if !seen.Equals(s) {
+		t.Errorf("All() yielded %v, want %v", seen, s)
+	}
+
+	// Breaking out of the loop early must stop the iteration.
+	count := 0
+	for range s.All() {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("All() yielded %d elements after break, want 3", count)
+	}
+
+	for range NewHashSet[int]().All() {
+		t.Error("All() on an empty set should not yield")
+	}
+}
+
+func TestCollectAndInsert(t *testing.T) {
+	s := Collect(slices.Values([]string{"a", "b", "a", "c"}))
+	if s.Cardinality() != 3 {
+		t.Errorf("Collect() cardinality = %d, want 3", s.Cardinality())
+	}
+	for _, elem := range []string{"a", "b", "c"} {
+		if !s.Contains(elem) {
+			t.Errorf("Collect() result missing %q", elem)
+		}
+	}
+
+	if got := Collect(slices.Values([]int(nil))); !got.IsEmpty() {
+		t.Errorf("Collect() of empty sequence = %v, want empty set", got)
+	}
+
+	Insert(s, maps.Keys(map[string]int{"c": 3, "d": 4}))
+	if s.Cardinality() != 4 || !s.Contains("d") {
+		t.Errorf("Insert() = %v, want {a, b, c, d}", s)
+	}
+
+	// Sets are themselves sequences of elements.
+	clone := Collect(s.All())
+	if !clone.Equals(s) {
+		t.Errorf("Collect(s.All()) = %v, want %v", clone, s)
+	}
+	clone.Insert("e")
+	if s.Contains("e") {
+		t.Error("Modifying a collected set affected the original")
+	}
+}
+
+func TestBulkOperations(t *testing.T) {
+	t.Run("hashSet", func(t *testing.T) { testBulkOperations(t, NewHashSet[int], identity) })
+	t.Run("ConcurrentSet", func(t *testing.T) {
+		testBulkOperations(t, func() Set[int] { return NewConcurrentSet[int]() }, identity)
+	})
+	t.Run("shardedSet", func(t *testing.T) {
+		testBulkOperations(t, func() Set[int] { return NewShardedSet[int](4) }, identity)
+	})
+	t.Run("SortedSet", func(t *testing.T) {
+		testBulkOperations(t, func() Set[int] { return NewSortedSet[int]() }, identity)
+	})
+	t.Run("LinkedHashSet", func(t *testing.T) {
+		testBulkOperations(t, func() Set[int] { return NewLinkedHashSet[int]() }, identity)
+	})
+	t.Run("PersistentSet", func(t *testing.T) {
+		testBulkOperations(t, func() Set[int] { return NewPersistentSet[int]() }, identity)
+	})
+	t.Run("BitSet", func(t *testing.T) {
+		testBulkOperations(t, func() Set[uint] { return NewBitSet() }, func(i int) uint { return uint(i) })
+	})
+	t.Run("RoaringBitmap", func(t *testing.T) {
+		// Spread the elements over several containers.
+		testBulkOperations(t, func() Set[uint32] { return NewRoaringBitmap() }, func(i int) uint32 { return uint32(i) << 15 })
+	})
+	t.Run("mockSet", func(t *testing.T) {
+		testBulkOperations(t, func() Set[int] { return newMockSet[int]() }, identity)
+	})
+}
+
+func identity(i int) int { return i }
+
+// testBulkOperations checks the bulk operations of the sets returned by newSet,
+// whose elements are built from integers with conv.
+func testBulkOperations[T comparable](t *testing.T, newSet func() Set[T], conv func(int) T) {
+	build := func(elems ...int) Set[T] {
+		s := newSet()
+		for _, elem := range elems {
+			s.Insert(conv(elem))
+		}
+		return s
+	}
+	mock := func(elems ...int) Set[T] {
+		m := newMockSet[T]()
+		for _, elem := range elems {
+			m.Insert(conv(elem))
+		}
+		return m
+	}
+
+	tests := []struct {
+		name     string
+		op       func(s Set[T]) int
+		count    int
+		expected []int
+	}{
+		{"InsertAll", func(s Set[T]) int { return s.InsertAll(conv(5), conv(7), conv(7)) }, 1, []int{1, 2, 3, 4, 5, 6, 7}},
+		{"InsertAll none", func(s Set[T]) int { return s.InsertAll() }, 0, []int{1, 2, 3, 4, 5, 6}},
+		{"RemoveAll", func(s Set[T]) int { return s.RemoveAll(conv(1), conv(9), conv(1)) }, 1, []int{2, 3, 4, 5, 6}},
+		{"RetainIf", func(s Set[T]) int { return s.RetainIf(mock(2, 4, 6).Contains) }, 3, []int{2, 4, 6}},
+		{"RemoveIf", func(s Set[T]) int { return s.RemoveIf(mock(1, 2).Contains) }, 2, []int{3, 4, 5, 6}},
+		{"Clear", func(s Set[T]) int { return s.Clear() }, 6, nil},
+		{"UnionWith", func(s Set[T]) int { return s.UnionWith(build(4, 5, 6, 7, 8, 9)) }, 3, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"UnionWith mockSet", func(s Set[T]) int { return s.UnionWith(mock(4, 5, 6, 7, 8, 9)) }, 3, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"UnionWith itself", func(s Set[T]) int { return s.UnionWith(s) }, 0, []int{1, 2, 3, 4, 5, 6}},
+		{"IntersectWith", func(s Set[T]) int { return s.IntersectWith(build(4, 5, 6, 7, 8, 9)) }, 3, []int{4, 5, 6}},
+		{"IntersectWith mockSet", func(s Set[T]) int { return s.IntersectWith(mock(4, 5, 6, 7, 8, 9)) }, 3, []int{4, 5, 6}},
+		{"IntersectWith itself", func(s Set[T]) int { return s.IntersectWith(s) }, 0, []int{1, 2, 3, 4, 5, 6}},
+		{"DifferenceWith", func(s Set[T]) int { return s.DifferenceWith(build(4, 5, 6, 7, 8, 9)) }, 3, []int{1, 2, 3}},
+		{"DifferenceWith mockSet", func(s Set[T]) int { return s.DifferenceWith(mock(4, 5, 6, 7, 8, 9)) }, 3, []int{1, 2, 3}},
+		{"DifferenceWith smaller", func(s Set[T]) int { return s.DifferenceWith(mock(6, 10)) }, 1, []int{1, 2, 3, 4, 5}},
+		{"DifferenceWith itself", func(s Set[T]) int { return s.DifferenceWith(s) }, 6, nil},
+		{"SymmetricDifferenceWith", func(s Set[T]) int { return s.SymmetricDifferenceWith(build(4, 5, 6, 7, 8, 9)) }, 6, []int{1, 2, 3, 7, 8, 9}},
+		{"SymmetricDifferenceWith mockSet", func(s Set[T]) int { return s.SymmetricDifferenceWith(mock(4, 5, 6, 7, 8, 9)) }, 6, []int{1, 2, 3, 7, 8, 9}},
+		{"SymmetricDifferenceWith itself", func(s Set[T]) int { return s.SymmetricDifferenceWith(s) }, 6, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := build(1, 2, 3, 4, 5, 6)
+			if got := tt.op(s); got != tt.count {
+				t.Errorf("%s() = %d, want %d", tt.name, got, tt.count)
+			}
+			if expected := mock(tt.expected...); !s.Equals(expected) || s.Cardinality() != len(tt.expected) {
+				t.Errorf("after %s() set = %v, want %v", tt.name, s, expected)
+			}
+		})
+	}
+
+	t.Run("Pop", func(t *testing.T) {
+		s := build(1, 2, 3, 4, 5, 6)
+		popped := newMockSet[T]()
+		for {
+			elem, ok := s.Pop()
+			if !ok {
+				break
+			}
+			if popped.Contains(elem) || s.Contains(elem) {
+				t.Errorf("Pop() returned %v, which was already popped or is still present", elem)
+			}
+			popped.Insert(elem)
+		}
+		if !s.IsEmpty() || !popped.Equals(mock(1, 2, 3, 4, 5, 6)) {
+			t.Errorf("Pop() returned %v and left %v", popped, s)
+		}
+	})
+}
@@ -0,0 +1,349 @@
+package set
+
+import (
+	"fmt"
+	"hash/maphash"
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// shard is one independently locked partition of a shardedSet.
+type shard[T comparable] struct {
+	mu       sync.RWMutex
+	elements map[T]struct{}
+
+	// Padding keeps neighbouring shards on different cache lines so that
+	// writers to different shards do not contend through false sharing.
+	_ [64]byte
+}
+
+// shardedSet implements the Set interface by partitioning its elements over a
+// fixed number of independently locked shards. An element's shard is chosen by
+// hashing it, so goroutines working on different elements rarely contend for
+// the same lock. It is safe for use by multiple goroutines.
+//
+// Single-element operations are atomic. Operations that visit the whole set,
+// such as Union or ToSlice, lock one shard at a time and therefore do not
+// observe a single point-in-time snapshot while writers are active. Likewise,
+// bulk operations such as InsertAll or IntersectWith are applied element by
+// element or shard by shard and are not atomic as a whole. Use ConcurrentSet
+// when such snapshots are required.
+type shardedSet[T comparable] struct {
+	seed   maphash.Seed
+	mask   uint64
+	shards []shard[T]
+
+	// size is maintained on every successful insertion and removal so that
+	// Cardinality does not need to lock every shard.
+	size atomic.Int64
+}
+
+// NewShardedSet creates and returns a new empty set that is safe for concurrent
+// use and is optimized for many simultaneous writers.
+//
+// The number of shards is rounded up to a power of two. If shards is not
+// positive, a default based on GOMAXPROCS is used.
+func NewShardedSet[T comparable](shards int) Set[T] {
+	return newShardedSet[T](shards)
+}
+
+func newShardedSet[T comparable](shards int) *shardedSet[T] {
+	if shards <= 0 {
+		shards = 4 * runtime.GOMAXPROCS(0)
+	}
+	n := 1
+	for n < shards {
+		n <<= 1
+	}
+
+	s := &shardedSet[T]{
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(n - 1),
+		shards: make([]shard[T], n),
+	}
+	for i := range s.shards {
+		s.shards[i].elements = make(map[T]struct{})
+	}
+	return s
+}
+
+// empty returns a new empty shardedSet with the same number of shards.
+func (s *shardedSet[T]) empty() *shardedSet[T] {
+	return newShardedSet[T](len(s.shards))
+}
+
+func (s *shardedSet[T]) emptyLike() Set[T] {
+	return s.empty()
+}
+
+func (s *shardedSet[T]) shardFor(elem T) *shard[T] {
+	return &s.shards[maphash.Comparable(s.seed, elem)&s.mask]
+}
+
+// add inserts elem and reports whether it was added.
+func (s *shardedSet[T]) add(elem T) bool {
+	sh := s.shardFor(elem)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if _, exists := sh.elements[elem]; exists {
+		return false
+	}
+	sh.elements[elem] = struct{}{}
+	s.size.Add(1)
+	return true
+}
+
+// del removes elem and reports whether it was present.
+func (s *shardedSet[T]) del(elem T) bool {
+	sh := s.shardFor(elem)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if _, exists := sh.elements[elem]; !exists {
+		return false
+	}
+	delete(sh.elements, elem)
+	s.size.Add(-1)
+	return true
+}
+
+func (s *shardedSet[T]) Insert(elem T) {
+	s.add(elem)
+}
+
+func (s *shardedSet[T]) Remove(elem T) {
+	s.del(elem)
+}
+
+func (s *shardedSet[T]) InsertAll(elems ...T) int {
+	added := 0
+	for _, elem := range elems {
+		if s.add(elem) {
+			added++
+		}
+	}
+	return added
+}
+
+func (s *shardedSet[T]) RemoveAll(elems ...T) int {
+	removed := 0
+	for _, elem := range elems {
+		if s.del(elem) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// RetainIf deletes every element for which keep returns false and returns the
+// number of elements deleted. Each shard is copied under its read lock and
+// keep is called without holding any lock, so it may use the set. Elements
+// inserted concurrently may not be visited.
+func (s *shardedSet[T]) RetainIf(keep func(T) bool) int {
+	return s.RemoveIf(func(elem T) bool { return !keep(elem) })
+}
+
+// RemoveIf deletes every element for which remove returns true and returns the
+// number of elements deleted. Each shard is copied under its read lock and
+// remove is called without holding any lock, so it may use the set. Elements
+// inserted concurrently may not be visited.
+func (s *shardedSet[T]) RemoveIf(remove func(T) bool) int {
+	removed := 0
+	for i := range s.shards {
+		sh := &s.shards[i]
+		var doomed []T
+		for _, elem := range sh.snapshot() {
+			if remove(elem) {
+				doomed = append(doomed, elem)
+			}
+		}
+		if len(doomed) > 0 {
+			removed += s.deleteFrom(sh, doomed)
+		}
+	}
+	return removed
+}
+
+// deleteFrom removes elems from sh and returns how many were still present.
+func (s *shardedSet[T]) deleteFrom(sh *shard[T], elems []T) int {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	before := len(sh.elements)
+	for _, elem := range elems {
+		delete(sh.elements, elem)
+	}
+	removed := before - len(sh.elements)
+	s.size.Add(int64(-removed))
+	return removed
+}
+
+// Clear empties one shard at a time and returns the number of elements deleted.
+func (s *shardedSet[T]) Clear() int {
+	removed := 0
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		n := len(sh.elements)
+		clear(sh.elements)
+		s.size.Add(int64(-n))
+		sh.mu.Unlock()
+		removed += n
+	}
+	return removed
+}
+
+func (s *shardedSet[T]) Pop() (T, bool) {
+	for i := range s.shards {
+		if elem, ok := s.shards[i].pop(&s.size); ok {
+			return elem, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// pop removes an element from sh, decrementing size, and returns it.
+func (sh *shard[T]) pop(size *atomic.Int64) (T, bool) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	for elem := range sh.elements {
+		delete(sh.elements, elem)
+		size.Add(-1)
+		return elem, true
+	}
+	var zero T
+	return zero, false
+}
+
+func (s *shardedSet[T]) Contains(elem T) bool {
+	sh := s.shardFor(elem)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	_, exists := sh.elements[elem]
+	return exists
+}
+
+func (s *shardedSet[T]) Cardinality() int {
+	return int(s.size.Load())
+}
+
+func (s *shardedSet[T]) IsEmpty() bool {
+	return s.size.Load() == 0
+}
+
+// All returns an iterator over the elements of the set. Each shard is copied
+// under its read lock and yielded without holding any lock, so the loop body
+// may modify the set.
+func (s *shardedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range s.shards {
+			for _, elem := range s.shards[i].snapshot() {
+				if !yield(elem) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (sh *shard[T]) snapshot() []T {
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	result := make([]T, 0, len(sh.elements))
+	for elem := range sh.elements {
+		result = append(result, elem)
+	}
+	return result
+}
+
+func (s *shardedSet[T]) ToSlice() []T {
+	result := make([]T, 0, s.Cardinality())
+	for i := range s.shards {
+		result = append(result, s.shards[i].snapshot()...)
+	}
+	return result
+}
+
+func (s *shardedSet[T]) String() string {
+	return formatLiteral(sortForDisplay(s.ToSlice()), formatElement)
+}
+
+// Format implements fmt.Formatter.
+func (s *shardedSet[T]) Format(f fmt.State, verb rune) {
+	newSet := fmt.Sprintf("set.NewShardedSet[%s](%d)", typeArg[T](), len(s.shards))
+	formatSet(f, verb, sortForDisplay(s.ToSlice()), newSet, "set.Set["+typeArg[T]()+"]")
+}
+
+func (s *shardedSet[T]) Equals(other Set[T]) bool {
+	return equal(s, other)
+}
+
+func (s *shardedSet[T]) IsSubsetOf(other Set[T]) bool {
+	return isSubset(s, other)
+}
+
+func (s *shardedSet[T]) IsSupersetOf(other Set[T]) bool {
+	return isSubset(other, s)
+}
+
+func (s *shardedSet[T]) IsProperSubsetOf(other Set[T]) bool {
+	return s.Cardinality() < other.Cardinality() && s.IsSubsetOf(other)
+}
+
+func (s *shardedSet[T]) IsProperSupersetOf(other Set[T]) bool {
+	return s.Cardinality() > other.Cardinality() && s.IsSupersetOf(other)
+}
+
+func (s *shardedSet[T]) Union(other Set[T]) Set[T] {
+	return unionInto(s.empty(), s, other)
+}
+
+func (s *shardedSet[T]) Intersection(other Set[T]) Set[T] {
+	return intersectionInto(s.empty(), s, other)
+}
+
+func (s *shardedSet[T]) Difference(other Set[T]) Set[T] {
+	return differenceInto(s.empty(), s, other)
+}
+
+func (s *shardedSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return symmetricDifferenceInto(s.empty(), s, other)
+}
+
+func (s *shardedSet[T]) UnionWith(other Set[T]) int {
+	added := 0
+	for elem := range other.All() {
+		if s.add(elem) {
+			added++
+		}
+	}
+	return added
+}
+
+func (s *shardedSet[T]) IntersectWith(other Set[T]) int {
+	return s.RemoveIf(func(elem T) bool { return !other.Contains(elem) })
+}
+
+func (s *shardedSet[T]) DifferenceWith(other Set[T]) int {
+	if other.Cardinality() >= s.Cardinality() {
+		return s.RemoveIf(other.Contains)
+	}
+	removed := 0
+	for elem := range other.All() {
+		if s.del(elem) {
+			removed++
+		}
+	}
+	return removed
+}
+
+func (s *shardedSet[T]) SymmetricDifferenceWith(other Set[T]) int {
+	elems := other.ToSlice()
+	for _, elem := range elems {
+		if !s.del(elem) {
+			s.add(elem)
+		}
+	}
+	return len(elems)
+}
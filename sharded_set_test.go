@@ -0,0 +1,201 @@
+package set
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShardedSetShardCount(t *testing.T) {
+	tests := []struct {
+		shards int
+		want   int
+	}{
+		{shards: 1, want: 1},
+		{shards: 3, want: 4},
+		{shards: 16, want: 16},
+		{shards: 17, want: 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("shards=%d", tt.shards), func(t *testing.T) {
+			if got := len(newShardedSet[int](tt.shards).shards); got != tt.want {
+				t.Errorf("shard count = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if got := len(newShardedSet[int](0).shards); got == 0 {
+		t.Error("default shard count should not be zero")
+	}
+}
+
+func TestShardedSetOperations(t *testing.T) {
+	a := NewShardedSet[int](4)
+	b := NewShardedSet[int](8)
+	for _, v := range []int{1, 2, 3, 4} {
+		a.Insert(v)
+	}
+	for _, v := range []int{3, 4, 5} {
+		b.Insert(v)
+	}
+
+	tests := []struct {
+		name     string
+		result   Set[int]
+		expected []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersection", a.Intersection(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"Union with mockSet", a.Union(newMockSet(9)), []int{1, 2, 3, 4, 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := tt.result.(*shardedSet[int]); !ok {
+				t.Errorf("%s returned %T, want *shardedSet[int]", tt.name, tt.result)
+			}
+			if !tt.result.Equals(newMockSet(tt.expected...)) {
+				t.Errorf("%s = %v, want %v", tt.name, tt.result, tt.expected)
+			}
+		})
+	}
+
+	if !a.IsProperSupersetOf(a.Intersection(b)) || a.IsProperSubsetOf(a) || !b.IsSupersetOf(newMockSet(5)) {
+		t.Error("subset relations are incorrect")
+	}
+	if !NewHashSet[int]().IsProperSubsetOf(a) || !a.IsSubsetOf(a.Union(b)) {
+		t.Error("subset relations with other implementations are incorrect")
+	}
+
+	a.Insert(1)
+	a.Remove(100)
+	if a.Cardinality() != 4 || a.IsEmpty() {
+		t.Errorf("Cardinality() = %d, want 4", a.Cardinality())
+	}
+	if got := len(a.ToSlice()); got != 4 {
+		t.Errorf("len(ToSlice()) = %d, want 4", got)
+	}
+
+	words := NewShardedSet[string](2)
+	for _, w := range []string{"c", "a", "b"} {
+		words.Insert(w)
+	}
+	if got := words.String(); got != "{a, b, c}" {
+		t.Errorf("String() = %q, want %q", got, "{a, b, c}")
+	}
+}
+
+func TestShardedSetRace(t *testing.T) {
+	const workers = 8
+	const perWorker = 1000
+
+	s := NewShardedSet[int](0)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				s.Insert(i)
+				_ = s.Contains(i)
+				_ = s.Cardinality()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Cardinality() != perWorker {
+		t.Errorf("Cardinality() = %d, want %d", s.Cardinality(), perWorker)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i += 2 {
+				s.Remove(i)
+			}
+			for range s.All() {
+			}
+		}()
+	}
+	wg.Wait()
+
+	if s.Cardinality() != perWorker/2 {
+		t.Errorf("Cardinality() = %d, want %d", s.Cardinality(), perWorker/2)
+	}
+}
+
+func TestShardedSetBulkRace(t *testing.T) {
+	const n = 1000
+
+	elems := make([]int, n)
+	for i := range elems {
+		elems[i] = i
+	}
+
+	// Bulk operations are not atomic as a whole, but every element is still
+	// counted by exactly one worker.
+	s := NewShardedSet[int](0)
+	var added, removed, popped atomic.Int64
+	runWorkers(8, func() { added.Add(int64(s.InsertAll(elems...))) })
+	runWorkers(8, func() {
+		removed.Add(int64(s.RemoveIf(func(elem int) bool { return elem%2 == 0 })))
+	})
+	runWorkers(8, func() {
+		for range 10 {
+			if _, ok := s.Pop(); ok {
+				popped.Add(1)
+			}
+		}
+	})
+
+	if added.Load() != n || removed.Load() != n/2 || popped.Load() != 80 {
+		t.Errorf("InsertAll added %d, RemoveIf removed %d and Pop popped %d, want %d, %d and 80",
+			added.Load(), removed.Load(), popped.Load(), n, n/2)
+	}
+	if want := n/2 - 80; s.Cardinality() != want || len(s.ToSlice()) != want {
+		t.Errorf("Cardinality() = %d, want %d", s.Cardinality(), want)
+	}
+	if cleared := s.Clear(); cleared != n/2-80 || !s.IsEmpty() {
+		t.Errorf("Clear() = %d, want %d", cleared, n/2-80)
+	}
+}
+
+func BenchmarkConcurrentInsertContains(b *testing.B) {
+	implementations := []struct {
+		name string
+		new  func() Set[int]
+	}{
+		{"ConcurrentSet", func() Set[int] { return NewConcurrentSet[int]() }},
+		{"ShardedSet", func() Set[int] { return NewShardedSet[int](0) }},
+	}
+	writeRatios := []int{10, 50, 90}
+
+	for _, impl := range implementations {
+		for _, writes := range writeRatios {
+			name := fmt.Sprintf("%s/writes=%d%%", impl.name, writes)
+			b.Run(name, func(b *testing.B) {
+				s := impl.new()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					i := 0
+					for pb.Next() {
+						key := i % 100000
+						if i%100 < writes {
+							s.Insert(key)
+						} else {
+							_ = s.Contains(key)
+						}
+						i++
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,571 @@
+package set
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// SortedSet implements the Set interface for ordered element types using an
+// AVL tree, a self-balancing binary search tree. Elements are kept in ascending
+// order as defined by cmp.Compare, so iteration, ToSlice and String are
+// deterministic.
+//
+// Every node records the size of its subtree, which makes the tree an order
+// statistic tree: Rank and Select run in O(log n) alongside Insert, Remove
+// and Contains.
+//
+// The zero value of a SortedSet is an empty set ready to use.
+// A SortedSet must not be modified while it is being iterated over.
+type SortedSet[T cmp.Ordered] struct {
+	root *sortedNode[T]
+}
+
+// NewSortedSet creates and returns a new empty sorted set.
+func NewSortedSet[T cmp.Ordered]() *SortedSet[T] {
+	return &SortedSet[T]{}
+}
+
+func (s *SortedSet[T]) emptyLike() Set[T] {
+	return NewSortedSet[T]()
+}
+
+// sortedNode is a node of the AVL tree backing a SortedSet.
+type sortedNode[T cmp.Ordered] struct {
+	value       T
+	left, right *sortedNode[T]
+	height      int
+	size        int
+}
+
+func (n *sortedNode[T]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *sortedNode[T]) getSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// update recomputes the height and size of n from its children.
+func (n *sortedNode[T]) update() {
+	n.height = 1 + max(n.left.getHeight(), n.right.getHeight())
+	n.size = 1 + n.left.getSize() + n.right.getSize()
+}
+
+func (n *sortedNode[T]) rotateRight() *sortedNode[T] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func (n *sortedNode[T]) rotateLeft() *sortedNode[T] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+// rebalance restores the AVL invariant at n, where the heights of the two
+// subtrees may differ by at most one, and returns the new subtree root.
+func (n *sortedNode[T]) rebalance() *sortedNode[T] {
+	n.update()
+	switch balance := n.left.getHeight() - n.right.getHeight(); {
+	case balance > 1:
+		if n.left.left.getHeight() < n.left.right.getHeight() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case balance < -1:
+		if n.right.right.getHeight() < n.right.left.getHeight() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+// insert adds value to the subtree rooted at n and returns the new root and
+// whether the value was added.
+func (n *sortedNode[T]) insert(value T) (*sortedNode[T], bool) {
+	if n == nil {
+		return &sortedNode[T]{value: value, height: 1, size: 1}, true
+	}
+
+	var added bool
+	switch c := cmp.Compare(value, n.value); {
+	case c < 0:
+		n.left, added = n.left.insert(value)
+	case c > 0:
+		n.right, added = n.right.insert(value)
+	default:
+		return n, false
+	}
+	if !added {
+		return n, false
+	}
+	return n.rebalance(), true
+}
+
+// remove deletes value from the subtree rooted at n and returns the new root
+// and whether the value was removed.
+func (n *sortedNode[T]) remove(value T) (*sortedNode[T], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var removed bool
+	switch c := cmp.Compare(value, n.value); {
+	case c < 0:
+		n.left, removed = n.left.remove(value)
+	case c > 0:
+		n.right, removed = n.right.remove(value)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// Replace the value with its in-order successor, then remove the
+		// successor from the right subtree.
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.value = successor.value
+		n.right, _ = n.right.remove(successor.value)
+		removed = true
+	}
+	if !removed {
+		return n, false
+	}
+	return n.rebalance(), true
+}
+
+// ascend yields the values of the subtree rooted at n in ascending order and
+// reports whether iteration should continue.
+func (n *sortedNode[T]) ascend(yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.ascend(yield) && yield(n.value) && n.right.ascend(yield)
+}
+
+// descend yields the values of the subtree rooted at n in descending order and
+// reports whether iteration should continue.
+func (n *sortedNode[T]) descend(yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.right.descend(yield) && yield(n.value) && n.left.descend(yield)
+}
+
+// ascendRange yields the values v of the subtree rooted at n with lo ≤ v < hi
+// in ascending order, skipping subtrees that lie outside the range.
+func (n *sortedNode[T]) ascendRange(lo, hi T, yield func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := cmp.Compare(lo, n.value) <= 0
+	belowHi := cmp.Compare(n.value, hi) < 0
+	if aboveLo && !n.left.ascendRange(lo, hi, yield) {
+		return false
+	}
+	if aboveLo && belowHi && !yield(n.value) {
+		return false
+	}
+	if belowHi {
+		return n.right.ascendRange(lo, hi, yield)
+	}
+	return true
+}
+
+// buildSorted returns a perfectly balanced tree holding elems, which must be
+// sorted in ascending order and free of duplicates. It runs in O(n).
+func buildSorted[T cmp.Ordered](elems []T) *sortedNode[T] {
+	if len(elems) == 0 {
+		return nil
+	}
+	mid := len(elems) / 2
+	n := &sortedNode[T]{
+		value: elems[mid],
+		left:  buildSorted(elems[:mid]),
+		right: buildSorted(elems[mid+1:]),
+	}
+	n.update()
+	return n
+}
+
+func (s *SortedSet[T]) Insert(elem T) {
+	s.root, _ = s.root.insert(elem)
+}
+
+func (s *SortedSet[T]) Remove(elem T) {
+	s.root, _ = s.root.remove(elem)
+}
+
+func (s *SortedSet[T]) InsertAll(elems ...T) int {
+	added := 0
+	for _, elem := range elems {
+		var ok bool
+		if s.root, ok = s.root.insert(elem); ok {
+			added++
+		}
+	}
+	return added
+}
+
+func (s *SortedSet[T]) RemoveAll(elems ...T) int {
+	removed := 0
+	for _, elem := range elems {
+		var ok bool
+		if s.root, ok = s.root.remove(elem); ok {
+			removed++
+		}
+	}
+	return removed
+}
+
+// RetainIf deletes every element for which keep returns false and returns the
+// number of elements deleted. keep is called in ascending order, and the tree
+// is rebuilt from the remaining elements in O(n).
+func (s *SortedSet[T]) RetainIf(keep func(T) bool) int {
+	return s.RemoveIf(func(elem T) bool { return !keep(elem) })
+}
+
+// RemoveIf deletes every element for which remove returns true and returns the
+// number of elements deleted. remove is called in ascending order, and the
+// tree is rebuilt from the remaining elements in O(n).
+func (s *SortedSet[T]) RemoveIf(remove func(T) bool) int {
+	return s.replace(slices.DeleteFunc(s.ToSlice(), remove))
+}
+
+// replace rebuilds the tree from sorted and returns the absolute change in the
+// number of elements.
+func (s *SortedSet[T]) replace(sorted []T) int {
+	before := s.Cardinality()
+	s.root = buildSorted(sorted)
+	return max(before-len(sorted), len(sorted)-before)
+}
+
+func (s *SortedSet[T]) Clear() int {
+	n := s.Cardinality()
+	s.root = nil
+	return n
+}
+
+// Pop deletes the smallest element of the set and returns it.
+// The boolean is false if the set is empty.
+func (s *SortedSet[T]) Pop() (T, bool) {
+	elem, ok := s.Min()
+	if ok {
+		s.Remove(elem)
+	}
+	return elem, ok
+}
+
+func (s *SortedSet[T]) Contains(elem T) bool {
+	n := s.root
+	for n != nil {
+		switch c := cmp.Compare(elem, n.value); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SortedSet[T]) Cardinality() int {
+	return s.root.getSize()
+}
+
+func (s *SortedSet[T]) IsEmpty() bool {
+	return s.root == nil
+}
+
+// Min returns the smallest element of the set.
+// The boolean is false if the set is empty.
+func (s *SortedSet[T]) Min() (T, bool) {
+	if s.root == nil {
+		var zero T
+		return zero, false
+	}
+	n := s.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.value, true
+}
+
+// Max returns the largest element of the set.
+// The boolean is false if the set is empty.
+func (s *SortedSet[T]) Max() (T, bool) {
+	if s.root == nil {
+		var zero T
+		return zero, false
+	}
+	n := s.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.value, true
+}
+
+// Floor returns the largest element of the set that is less than or equal to x.
+// The boolean is false if no such element exists.
+func (s *SortedSet[T]) Floor(x T) (T, bool) {
+	var result T
+	found := false
+	for n := s.root; n != nil; {
+		switch c := cmp.Compare(x, n.value); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			result, found = n.value, true
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	return result, found
+}
+
+// Ceiling returns the smallest element of the set that is greater than or equal to x.
+// The boolean is false if no such element exists.
+func (s *SortedSet[T]) Ceiling(x T) (T, bool) {
+	var result T
+	found := false
+	for n := s.root; n != nil; {
+		switch c := cmp.Compare(x, n.value); {
+		case c < 0:
+			result, found = n.value, true
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	return result, found
+}
+
+// Rank returns the number of elements of the set that are strictly less than x.
+// If x is in the set, this is its zero-based index in ascending order.
+func (s *SortedSet[T]) Rank(x T) int {
+	rank := 0
+	for n := s.root; n != nil; {
+		if cmp.Compare(x, n.value) <= 0 {
+			n = n.left
+		} else {
+			rank += n.left.getSize() + 1
+			n = n.right
+		}
+	}
+	return rank
+}
+
+// Select returns the element with the given zero-based index in ascending order.
+// The boolean is false if the index is out of range.
+func (s *SortedSet[T]) Select(index int) (T, bool) {
+	if index < 0 || index >= s.Cardinality() {
+		var zero T
+		return zero, false
+	}
+	n := s.root
+	for {
+		leftSize := n.left.getSize()
+		switch {
+		case index < leftSize:
+			n = n.left
+		case index == leftSize:
+			return n.value, true
+		default:
+			index -= leftSize + 1
+			n = n.right
+		}
+	}
+}
+
+// All returns an iterator over the elements of the set in ascending order.
+func (s *SortedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.root.ascend(yield)
+	}
+}
+
+// Backward returns an iterator over the elements of the set in descending order.
+func (s *SortedSet[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.root.descend(yield)
+	}
+}
+
+// Range returns an iterator over the elements x of the set with lo ≤ x < hi,
+// in ascending order.
+func (s *SortedSet[T]) Range(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.root.ascendRange(lo, hi, yield)
+	}
+}
+
+// ToSlice returns a slice containing all elements in the set in ascending order.
+func (s *SortedSet[T]) ToSlice() []T {
+	result := make([]T, 0, s.Cardinality())
+	for elem := range s.All() {
+		result = append(result, elem)
+	}
+	return result
+}
+
+// String returns a string representation of the set with its elements in
+// ascending order.
+func (s *SortedSet[T]) String() string {
+	return formatLiteral(s.ToSlice(), formatElement)
+}
+
+// Format implements fmt.Formatter, listing the elements in ascending order.
+func (s *SortedSet[T]) Format(f fmt.State, verb rune) {
+	formatSet(f, verb, s.ToSlice(), "set.NewSortedSet["+typeArg[T]()+"]()", "*set.SortedSet["+typeArg[T]()+"]")
+}
+
+// sortedElements returns the elements of other in ascending order. Another
+// SortedSet is walked in order; any other implementation is sorted.
+func sortedElements[T cmp.Ordered](other Set[T]) []T {
+	if o, ok := other.(*SortedSet[T]); ok {
+		return o.ToSlice()
+	}
+	return slices.Sorted(other.All())
+}
+
+func (s *SortedSet[T]) Equals(other Set[T]) bool {
+	if o, ok := other.(*SortedSet[T]); ok {
+		return s.Cardinality() == o.Cardinality() && slices.Equal(s.ToSlice(), o.ToSlice())
+	}
+	return equal(s, other)
+}
+
+func (s *SortedSet[T]) IsSubsetOf(other Set[T]) bool {
+	return isSubset(s, other)
+}
+
+func (s *SortedSet[T]) IsSupersetOf(other Set[T]) bool {
+	return isSubset(other, s)
+}
+
+func (s *SortedSet[T]) IsProperSubsetOf(other Set[T]) bool {
+	return s.Cardinality() < other.Cardinality() && s.IsSubsetOf(other)
+}
+
+func (s *SortedSet[T]) IsProperSupersetOf(other Set[T]) bool {
+	return s.Cardinality() > other.Cardinality() && s.IsSupersetOf(other)
+}
+
+// merge walks the sorted sequences a and b in step and keeps the elements
+// selected by the flags: onlyA for elements only in a, onlyB for elements only
+// in b, and both for elements in both. It runs in O(len(a) + len(b)).
+func merge[T cmp.Ordered](a, b []T, onlyA, onlyB, both bool) []T {
+	result := make([]T, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch c := cmp.Compare(a[i], b[j]); {
+		case c < 0:
+			if onlyA {
+				result = append(result, a[i])
+			}
+			i++
+		case c > 0:
+			if onlyB {
+				result = append(result, b[j])
+			}
+			j++
+		default:
+			if both {
+				result = append(result, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if onlyA {
+		result = append(result, a[i:]...)
+	}
+	if onlyB {
+		result = append(result, b[j:]...)
+	}
+	return result
+}
+
+// Union returns a new SortedSet containing all elements that are in either
+// this set or the other set (**X** ∪ **Y**), computed by merging both sets in
+// ascending order.
+func (s *SortedSet[T]) Union(other Set[T]) Set[T] {
+	return &SortedSet[T]{root: buildSorted(merge(s.ToSlice(), sortedElements(other), true, true, true))}
+}
+
+// Intersection returns a new SortedSet containing all elements that are in both
+// this set and the other set (**X** ∩ **Y**), computed by merging both sets in
+// ascending order.
+func (s *SortedSet[T]) Intersection(other Set[T]) Set[T] {
+	return &SortedSet[T]{root: buildSorted(merge(s.ToSlice(), sortedElements(other), false, false, true))}
+}
+
+// Difference returns a new SortedSet containing all elements that are in this
+// set but not in the other set (**X** \ **Y**), computed by merging both sets
+// in ascending order.
+func (s *SortedSet[T]) Difference(other Set[T]) Set[T] {
+	return &SortedSet[T]{root: buildSorted(merge(s.ToSlice(), sortedElements(other), true, false, false))}
+}
+
+// SymmetricDifference returns a new SortedSet containing all elements that are
+// in either this set or the other set, but not in both (**X** Δ **Y**),
+// computed by merging both sets in ascending order.
+func (s *SortedSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return &SortedSet[T]{root: buildSorted(merge(s.ToSlice(), sortedElements(other), true, true, false))}
+}
+
+// UnionWith adds the elements of the other set to this set in place
+// (**X** ← **X** ∪ **Y**) and returns the number of elements added. The tree is
+// rebuilt by merging both sets in ascending order.
+func (s *SortedSet[T]) UnionWith(other Set[T]) int {
+	return s.replace(merge(s.ToSlice(), sortedElements(other), true, true, true))
+}
+
+// IntersectWith deletes the elements of this set that are not in the other set
+// (**X** ← **X** ∩ **Y**) and returns the number of elements deleted. The tree
+// is rebuilt by merging both sets in ascending order.
+func (s *SortedSet[T]) IntersectWith(other Set[T]) int {
+	return s.replace(merge(s.ToSlice(), sortedElements(other), false, false, true))
+}
+
+// DifferenceWith deletes the elements of this set that are in the other set
+// (**X** ← **X** \ **Y**) and returns the number of elements deleted. The tree
+// is rebuilt by merging both sets in ascending order.
+func (s *SortedSet[T]) DifferenceWith(other Set[T]) int {
+	return s.replace(merge(s.ToSlice(), sortedElements(other), true, false, false))
+}
+
+// SymmetricDifferenceWith deletes the elements of this set that are in the
+// other set and adds those that are not (**X** ← **X** Δ **Y**). It returns the
+// number of elements added or deleted. The tree is rebuilt by merging both sets
+// in ascending order.
+func (s *SortedSet[T]) SymmetricDifferenceWith(other Set[T]) int {
+	b := sortedElements(other)
+	s.root = buildSorted(merge(s.ToSlice(), b, true, true, false))
+	return len(b)
+}
@@ -0,0 +1,246 @@
+package set
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// checkAVL verifies the ordering, balance and size invariants of the tree
+// rooted at n and returns its height.
+func checkAVL[T int | string](t *testing.T, n *sortedNode[T]) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	if n.left != nil && n.left.value >= n.value {
+		t.Fatalf("left child %v is not less than %v", n.left.value, n.value)
+	}
+	if n.right != nil && n.right.value <= n.value {
+		t.Fatalf("right child %v is not greater than %v", n.right.value, n.value)
+	}
+	lh, rh := checkAVL(t, n.left), checkAVL(t, n.right)
+	if lh-rh > 1 || rh-lh > 1 {
+		t.Fatalf("node %v is unbalanced: left height %d, right height %d", n.value, lh, rh)
+	}
+	if n.height != 1+max(lh, rh) {
+		t.Fatalf("node %v has height %d, want %d", n.value, n.height, 1+max(lh, rh))
+	}
+	if n.size != 1+n.left.getSize()+n.right.getSize() {
+		t.Fatalf("node %v has incorrect size %d", n.value, n.size)
+	}
+	return n.height
+}
+
+func TestSortedSetRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	s := NewSortedSet[int]()
+	reference := NewHashSet[int]()
+
+	for i := 0; i < 5000; i++ {
+		v := r.IntN(500)
+		if r.IntN(3) == 0 {
+			s.Remove(v)
+			reference.Remove(v)
+		} else {
+			s.Insert(v)
+			reference.Insert(v)
+		}
+	}
+
+	checkAVL(t, s.root)
+	if !s.Equals(reference) || !reference.Equals(s) {
+		t.Fatalf("SortedSet diverged from reference: %v", s)
+	}
+	if want := slices.Sorted(reference.All()); !slices.Equal(s.ToSlice(), want) {
+		t.Errorf("ToSlice() = %v, want %v", s.ToSlice(), want)
+	}
+}
+
+func TestSortedSetOrderQueries(t *testing.T) {
+	var s SortedSet[int] // the zero value is ready to use
+	if _, ok := s.Min(); ok {
+		t.Error("Min() of empty set should report false")
+	}
+	if _, ok := s.Max(); ok {
+		t.Error("Max() of empty set should report false")
+	}
+
+	for _, v := range []int{50, 10, 40, 20, 30} {
+		s.Insert(v)
+	}
+
+	if got, _ := s.Min(); got != 10 {
+		t.Errorf("Min() = %d, want 10", got)
+	}
+	if got, _ := s.Max(); got != 50 {
+		t.Errorf("Max() = %d, want 50", got)
+	}
+
+	tests := []struct {
+		x           int
+		floor       int
+		floorOK     bool
+		ceiling     int
+		ceilingOK   bool
+		rank        int
+		description string
+	}{
+		{x: 5, ceiling: 10, ceilingOK: true, rank: 0, description: "below minimum"},
+		{x: 10, floor: 10, floorOK: true, ceiling: 10, ceilingOK: true, rank: 0, description: "minimum"},
+		{x: 25, floor: 20, floorOK: true, ceiling: 30, ceilingOK: true, rank: 2, description: "between elements"},
+		{x: 30, floor: 30, floorOK: true, ceiling: 30, ceilingOK: true, rank: 2, description: "member"},
+		{x: 60, floor: 50, floorOK: true, rank: 5, description: "above maximum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got, ok := s.Floor(tt.x); got != tt.floor || ok != tt.floorOK {
+				t.Errorf("Floor(%d) = %d, %v, want %d, %v", tt.x, got, ok, tt.floor, tt.floorOK)
+			}
+			if got, ok := s.Ceiling(tt.x); got != tt.ceiling || ok != tt.ceilingOK {
+				t.Errorf("Ceiling(%d) = %d, %v, want %d, %v", tt.x, got, ok, tt.ceiling, tt.ceilingOK)
+			}
+			if got := s.Rank(tt.x); got != tt.rank {
+				t.Errorf("Rank(%d) = %d, want %d", tt.x, got, tt.rank)
+			}
+		})
+	}
+
+	for i, want := range []int{10, 20, 30, 40, 50} {
+		if got, ok := s.Select(i); !ok || got != want {
+			t.Errorf("Select(%d) = %d, %v, want %d, true", i, got, ok, want)
+		}
+	}
+	for _, i := range []int{-1, 5} {
+		if _, ok := s.Select(i); ok {
+			t.Errorf("Select(%d) should report false", i)
+		}
+	}
+}
+
+func TestSortedSetIterators(t *testing.T) {
+	s := NewSortedSet[string]()
+	for _, w := range []string{"delta", "alpha", "echo", "charlie", "bravo"} {
+		s.Insert(w)
+	}
+
+	if got, want := slices.Collect(s.All()), []string{"alpha", "bravo", "charlie", "delta", "echo"}; !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(s.Backward()), []string{"echo", "delta", "charlie", "bravo", "alpha"}; !slices.Equal(got, want) {
+		t.Errorf("Backward() = %v, want %v", got, want)
+	}
+	if got, want := s.String(), "{alpha, bravo, charlie, delta, echo}"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	ranges := []struct {
+		lo, hi string
+		want   []string
+	}{
+		{"b", "d", []string{"bravo", "charlie"}},
+		{"bravo", "delta", []string{"bravo", "charlie"}},
+		{"a", "z", []string{"alpha", "bravo", "charlie", "delta", "echo"}},
+		{"f", "z", nil},
+		{"d", "a", nil},
+	}
+	for _, tt := range ranges {
+		if got := slices.Collect(s.Range(tt.lo, tt.hi)); !slices.Equal(got, tt.want) {
+			t.Errorf("Range(%q, %q) = %v, want %v", tt.lo, tt.hi, got, tt.want)
+		}
+	}
+
+	var firstTwo []string
+	for w := range s.Backward() {
+		firstTwo = append(firstTwo, w)
+		if len(firstTwo) == 2 {
+			break
+		}
+	}
+	if want := []string{"echo", "delta"}; !slices.Equal(firstTwo, want) {
+		t.Errorf("Backward() with break = %v, want %v", firstTwo, want)
+	}
+}
+
+func TestSortedSetOperations(t *testing.T) {
+	a := NewSortedSet[int]()
+	b := NewSortedSet[int]()
+	for _, v := range []int{1, 2, 3, 4} {
+		a.Insert(v)
+	}
+	for _, v := range []int{3, 4, 5} {
+		b.Insert(v)
+	}
+
+	tests := []struct {
+		name     string
+		result   Set[int]
+		expected []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersection", a.Intersection(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"Union with mockSet", a.Union(newMockSet(9, 0)), []int{0, 1, 2, 3, 4, 9}},
+		{"Intersection with mockSet", a.Intersection(newMockSet(4, 1, 7)), []int{1, 4}},
+		{"Difference with mockSet", a.Difference(newMockSet(2)), []int{1, 3, 4}},
+		{"SymmetricDifference with hashSet", a.SymmetricDifference(Collect(newMockSet(0, 4).All())), []int{0, 1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted, ok := tt.result.(*SortedSet[int])
+			if !ok {
+				t.Fatalf("%s returned %T, want *SortedSet[int]", tt.name, tt.result)
+			}
+			checkAVL(t, sorted.root)
+			if got := sorted.ToSlice(); !slices.Equal(got, tt.expected) {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+
+	if !a.IsProperSupersetOf(a.Intersection(b)) || a.IsProperSubsetOf(a) || !b.IsSupersetOf(newMockSet(5)) {
+		t.Error("subset relations are incorrect")
+	}
+	if a.Equals(b) || !a.Equals(a.Union(a)) || !a.IsSubsetOf(newMockSet(4, 3, 2, 1)) {
+		t.Error("equality is incorrect")
+	}
+}
+
+func TestSortedSetBulkOperations(t *testing.T) {
+	s := NewSortedSet[int]()
+	if added := s.InsertAll(5, 3, 9, 1, 7, 3); added != 5 {
+		t.Errorf("InsertAll() = %d, want 5", added)
+	}
+
+	var visited []int
+	s.RemoveIf(func(elem int) bool {
+		visited = append(visited, elem)
+		return elem == 7
+	})
+	if !slices.Equal(visited, []int{1, 3, 5, 7, 9}) {
+		t.Errorf("RemoveIf() visited %v, want ascending order", visited)
+	}
+
+	other := NewHashSet[int]()
+	for i := 2; i < 12; i += 2 {
+		other.Insert(i)
+	}
+	if added := s.UnionWith(other); added != 5 {
+		t.Errorf("UnionWith() = %d, want 5", added)
+	}
+	checkAVL(t, s.root)
+	if got := s.ToSlice(); !slices.Equal(got, []int{1, 2, 3, 4, 5, 6, 8, 9, 10}) {
+		t.Errorf("after UnionWith() set = %v", got)
+	}
+
+	if elem, ok := s.Pop(); !ok || elem != 1 {
+		t.Errorf("Pop() = %d, %v, want 1, true", elem, ok)
+	}
+	if removed := s.IntersectWith(other); removed != 3 {
+		t.Errorf("IntersectWith() = %d, want 3", removed)
+	}
+	checkAVL(t, s.root)
+}
@@ -0,0 +1,291 @@
+package set
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SQLFormat selects the text format in which SQL stores a set in a column.
+type SQLFormat int
+
+const (
+	// PostgresArray is the text format of PostgreSQL arrays, such as
+	// {a,"b c",d}. It supports elements whose underlying type is a string,
+	// integer, floating-point or boolean type.
+	PostgresArray SQLFormat = iota
+
+	// JSONText is a JSON array, as used for JSON columns in SQLite, MySQL or
+	// PostgreSQL. It supports every element type that encoding/json supports.
+	JSONText
+)
+
+// SQL adapts a set for use with database/sql: it implements driver.Valuer, so
+// it can be passed as a query argument, and sql.Scanner, so a pointer to it can
+// be passed to Scan. For example:
+//
+//	tags := set.NewHashSet[string]()
+//	err := db.QueryRow("SELECT tags FROM posts WHERE id = $1", id).Scan(&set.SQL[string]{Set: tags})
+//
+// Value writes the elements in sorted order, in the format given by Format.
+// Scan accepts either format regardless of Format, telling them apart by their
+// first character, and replaces the contents of Set with the scanned elements.
+// It also accepts the explicit bounds that PostgreSQL writes before arrays
+// whose lower bound is not 1, such as [0:2]={1,2,3}, and ignores them. If Set
+// is nil, Scan stores a new hash set in it. SQL NULL is scanned as the empty
+// set.
+type SQL[T comparable] struct {
+	Set    Set[T]
+	Format SQLFormat
+}
+
+// Value implements driver.Valuer. A nil Set is stored as SQL NULL.
+func (s SQL[T]) Value() (driver.Value, error) {
+	if isNil(s.Set) {
+		return nil, nil
+	}
+	elems := s.Set.ToSlice()
+	slices.SortFunc(elems, valueCompare[T]())
+
+	if s.Format == JSONText {
+		data, err := marshalJSON(elems, false)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	return formatPostgresArray(elems)
+}
+
+// Scan implements sql.Scanner.
+func (s *SQL[T]) Scan(src any) error {
+	var text string
+	switch src := src.(type) {
+	case nil:
+	case string:
+		text = src
+	case []byte:
+		text = string(src)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into a set", ErrInvalidSQLValue, src)
+	}
+
+	var elems []T
+	var err error
+	switch trimmed := trimArrayBounds(strings.TrimSpace(text)); {
+	case src == nil:
+	case strings.HasPrefix(trimmed, "["):
+		elems, err = decodeJSONElements[T]([]byte(trimmed), IgnoreDuplicates)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidSQLValue, err)
+		}
+	default:
+		elems, err = parsePostgresArray[T](trimmed)
+	}
+	if err != nil {
+		return err
+	}
+
+	if isNil(s.Set) {
+		s.Set = NewHashSet[T]()
+	}
+	if err := checkDecodedElements(s.Set, elems, len(text)); err != nil {
+		return err
+	}
+	replaceElements(s.Set, elems)
+	return nil
+}
+
+// trimArrayBounds returns text without the dimension decoration of a
+// PostgreSQL array with explicit bounds, such as the [0:2]= of [0:2]={1,2,3}.
+// Text without a well-formed decoration is returned unchanged.
+func trimArrayBounds(text string) string {
+	rest := text
+	for strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return text
+		}
+		lower, upper, ok := strings.Cut(rest[1:end], ":")
+		if !ok || !isInteger(lower) || !isInteger(upper) {
+			return text
+		}
+		rest = rest[end+1:]
+	}
+	if array, ok := strings.CutPrefix(rest, "="); ok && rest != text {
+		return array
+	}
+	return text
+}
+
+func isInteger(text string) bool {
+	_, err := strconv.Atoi(text)
+	return err == nil
+}
+
+// formatPostgresArray writes elems in the PostgreSQL array text format,
+// quoting elements that would otherwise be misread.
+func formatPostgresArray[T comparable](elems []T) (string, error) {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, elem := range elems {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		text, err := formatSQLElement(reflect.ValueOf(elem))
+		if err != nil {
+			return "", err
+		}
+		if !needsQuoting(text) {
+			sb.WriteString(text)
+			continue
+		}
+		sb.WriteByte('"')
+		for _, r := range text {
+			if r == '"' || r == '\\' {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(r)
+		}
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String(), nil
+}
+
+// needsQuoting reports whether an array element must be double-quoted: when it
+// is empty, is the word NULL, or contains a delimiter, quote, backslash or
+// white space.
+func needsQuoting(text string) bool {
+	return text == "" || strings.EqualFold(text, "NULL") || strings.ContainsAny(text, "{},\"\\ \t\n\r\v\f")
+}
+
+// formatSQLElement returns the text of a scalar element.
+func formatSQLElement(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	}
+	return "", fmt.Errorf("%w: %v elements cannot be stored as a PostgreSQL array", ErrInvalidSQLValue, v.Type())
+}
+
+// parseSQLElement converts the text of an element into a value of type T.
+func parseSQLElement[T comparable](text string) (T, error) {
+	var elem T
+	if err := parseScalar(reflect.ValueOf(&elem).Elem(), text); err != nil {
+		return elem, fmt.Errorf("%w: %w", ErrInvalidSQLValue, err)
+	}
+	return elem, nil
+}
+
+// parsePostgresArray parses a one-dimensional array in the PostgreSQL array
+// text format. NULL elements and nested arrays are rejected.
+func parsePostgresArray[T comparable](text string) ([]T, error) {
+	if len(text) < 2 || text[0] != '{' || text[len(text)-1] != '}' {
+		return nil, fmt.Errorf("%w: %q is not an array literal", ErrInvalidSQLValue, text)
+	}
+	body := text[1 : len(text)-1]
+	if strings.TrimSpace(body) == "" {
+		return []T{}, nil
+	}
+
+	var elems []T
+	for pos := 0; ; {
+		token, quoted, next, err := nextArrayElement(body, pos)
+		if err != nil {
+			return nil, err
+		}
+		if !quoted && strings.EqualFold(token, "NULL") {
+			return nil, fmt.Errorf("%w: NULL element at offset %d", ErrInvalidSQLValue, pos+1)
+		}
+		elem, err := parseSQLElement[T](token)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+		if next == len(body) {
+			return elems, nil
+		}
+		pos = next + 1 // skip the comma
+	}
+}
+
+// nextArrayElement reads the element of an array body starting at pos. It
+// returns the unescaped element, whether it was quoted, and the position of
+// the comma that follows it or the end of the body.
+func nextArrayElement(body string, pos int) (token string, quoted bool, next int, err error) {
+	pos = skipArraySpace(body, pos)
+	start := pos
+	quoted = pos < len(body) && body[pos] == '"'
+	if quoted {
+		token, pos, err = readQuotedElement(body, pos)
+	} else {
+		token, pos, err = readUnquotedElement(body, pos)
+	}
+	if err != nil {
+		return "", false, 0, err
+	}
+
+	pos = skipArraySpace(body, pos)
+	if pos < len(body) && body[pos] != ',' {
+		return "", false, 0, fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidSQLValue, body[pos], pos+1)
+	}
+	if !quoted && token == "" {
+		return "", false, 0, fmt.Errorf("%w: empty element at offset %d", ErrInvalidSQLValue, start+1)
+	}
+	return token, quoted, pos, nil
+}
+
+// readQuotedElement reads the double-quoted element starting at pos, removing
+// the backslash escapes, and returns it with the position after the closing
+// quote.
+func readQuotedElement(body string, pos int) (string, int, error) {
+	var sb strings.Builder
+	for i := pos + 1; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '"':
+			return sb.String(), i + 1, nil
+		case c == '\\' && i+1 < len(body):
+			i++
+			c = body[i]
+		}
+		sb.WriteByte(c)
+	}
+	return "", 0, fmt.Errorf("%w: unterminated quoted element at offset %d", ErrInvalidSQLValue, pos+1)
+}
+
+// readUnquotedElement reads the unquoted element starting at pos and returns
+// it without trailing white space, with the position of the following comma
+// or the end of the body.
+func readUnquotedElement(body string, pos int) (string, int, error) {
+	start := pos
+	for ; pos < len(body) && body[pos] != ','; pos++ {
+		if c := body[pos]; c == '{' || c == '}' || c == '"' {
+			return "", 0, fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidSQLValue, c, pos+1)
+		}
+	}
+	return strings.TrimRight(body[start:pos], " \t\n\r\v\f"), pos, nil
+}
+
+func skipArraySpace(body string, pos int) int {
+	for pos < len(body) && isArraySpace(body[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func isArraySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
@@ -0,0 +1,221 @@
+package set
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is a database/sql driver for a single key-value table, so that
+// the SQL adapter can be tested without a database. The statement
+// "PUT" stores its second argument under its first, "PUT BYTES" does the same
+// but returns the value as []byte when read, and "GET" reads the value stored
+// under its argument.
+type fakeDriver struct {
+	mu    sync.Mutex
+	table map[string]driver.Value
+}
+
+var (
+	registerFakeDriver sync.Once
+	theFakeDriver      = &fakeDriver{table: make(map[string]driver.Value)}
+)
+
+func openFakeDB(t *testing.T) *sql.DB {
+	t.Helper()
+	registerFakeDriver.Do(func() { sql.Register("setfake", theFakeDriver) })
+	db, err := sql.Open("setfake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.d, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("fake: no transactions") }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	value := args[1]
+	if text, ok := value.(string); ok && s.query == "PUT BYTES" {
+		value = []byte(text)
+	}
+	s.d.table[args[0].(string)] = value
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{value: s.d.table[args[0].(string)]}, nil
+}
+
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"value"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	db := openFakeDB(t)
+
+	tags := NewHashSet[string]()
+	tags.InsertAll("go", "a b", `say "hi"`, `back\slash`, "", "NULL", "{x,y}", "日本")
+	for _, format := range []SQLFormat{PostgresArray, JSONText} {
+		for _, query := range []string{"PUT", "PUT BYTES"} {
+			if _, err := db.Exec(query, "tags", SQL[string]{Set: tags, Format: format}); err != nil {
+				t.Fatal(err)
+			}
+			got := SQL[string]{Set: NewSortedSet[string]()}
+			if err := db.QueryRow("GET", "tags").Scan(&got); err != nil {
+				t.Fatalf("Scan() of format %d error = %v", format, err)
+			}
+			if !got.Set.Equals(tags) {
+				t.Errorf("format %d round trip = %v, want %v", format, got.Set, tags)
+			}
+			if _, ok := got.Set.(*SortedSet[string]); !ok {
+				t.Errorf("Scan() replaced the set with %T", got.Set)
+			}
+		}
+	}
+
+	ids := NewSortedSet[int]()
+	ids.InsertAll(3, -1, 2)
+	if _, err := db.Exec("PUT", "ids", SQL[int]{Set: ids}); err != nil {
+		t.Fatal(err)
+	}
+	var got SQL[int]
+	if err := db.QueryRow("GET", "ids").Scan(&got); err != nil || !got.Set.Equals(ids) {
+		t.Errorf("round trip into a nil set = %v, %v, want %v", got.Set, err, ids)
+	}
+
+	if _, err := db.Exec("PUT", "null", SQL[int]{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("GET", "null").Scan(&got); err != nil || !got.Set.IsEmpty() {
+		t.Errorf("Scan() of NULL = %v, %v, want the empty set", got.Set, err)
+	}
+}
+
+func TestSQLValue(t *testing.T) {
+	strs := newMockSet("b", "a c", `q"`, "", "null")
+	floats := newMockSet(2.5, -1.0)
+
+	tests := []struct {
+		name     string
+		value    driver.Valuer
+		expected string
+	}{
+		{"strings", SQL[string]{Set: strs}, `{"","a c",b,"null","q\""}`},
+		{"strings as JSON", SQL[string]{Set: strs, Format: JSONText}, `["","a c","b","null","q\""]`},
+		{"floats", SQL[float64]{Set: floats}, `{-1,2.5}`},
+		{"bools", SQL[bool]{Set: newMockSet(true, false)}, `{false,true}`},
+		{"pairs as JSON", SQL[Pair[int]]{Set: newMockSet(Pair[int]{2, 1}, Pair[int]{1, 2}), Format: JSONText},
+			`[{"First":1,"Second":2},{"First":2,"Second":1}]`},
+		{"empty", SQL[int]{Set: newMockSet[int]()}, `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.value.Value()
+			if err != nil || got != tt.expected {
+				t.Errorf("Value() = %v, %v, want %s", got, err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := (SQL[Pair[int]]{Set: newMockSet(Pair[int]{})}).Value(); !errors.Is(err, ErrInvalidSQLValue) {
+		t.Errorf("Value() of pairs as an array error = %v, want ErrInvalidSQLValue", err)
+	}
+}
+
+func TestSQLScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected []int
+		err      error
+	}{
+		{"array", "{3,1,2}", []int{1, 2, 3}, nil},
+		{"array with spaces and quotes", ` { 1 , "2" ,3 } `, []int{1, 2, 3}, nil},
+		{"array with duplicates", "{1,1}", []int{1}, nil},
+		{"empty array", "{}", nil, nil},
+		{"array with bounds", "[0:2]={3,1,2}", []int{1, 2, 3}, nil},
+		{"array with negative bounds", " [-1:0]={5,4}", []int{4, 5}, nil},
+		{"two-dimensional array with bounds", "[1:1][1:2]={{1,2}}", nil, ErrInvalidSQLValue},
+		{"bounds without array", "[0:2]=", nil, ErrInvalidSQLValue},
+		{"malformed bounds", "[0-2]={1}", nil, ErrInvalidSQLValue},
+		{"JSON", []byte("[5, 4]"), []int{4, 5}, nil},
+		{"NULL", nil, nil, nil},
+		{"NULL element", "{1,NULL}", nil, ErrInvalidSQLValue},
+		{"nested array", "{{1,2}}", nil, ErrInvalidSQLValue},
+		{"empty element", "{1,,2}", nil, ErrInvalidSQLValue},
+		{"unterminated quote", `{"1}`, nil, ErrInvalidSQLValue},
+		{"not an array", "1,2", nil, ErrInvalidSQLValue},
+		{"not a number", "{x}", nil, ErrInvalidSQLValue},
+		{"out of range", "{99999999999999999999}", nil, ErrInvalidSQLValue},
+		{"invalid JSON", "[1,", nil, ErrInvalidSQLValue},
+		{"unsupported source", 42, nil, ErrInvalidSQLValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := SQL[int]{Set: NewSortedSet[int]()}
+			s.Set.Insert(7)
+			err := s.Scan(tt.src)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Scan(%v) error = %v, want %v", tt.src, err, tt.err)
+			}
+			want := tt.expected
+			if err != nil {
+				want = []int{7}
+			}
+			if got := s.Set.ToSlice(); !slices.Equal(got, want) {
+				t.Errorf("Scan(%v) set = %v, want %v", tt.src, got, want)
+			}
+		})
+	}
+
+	s := SQL[string]{}
+	if err := s.Scan(`{a,"b\\c","d\"e", f g }`); err != nil || !s.Set.Equals(newMockSet("a", `b\c`, `d"e`, "f g")) {
+		t.Errorf("Scan() of escaped strings = %v, %v", s.Set, err)
+	}
+
+	b := NewBitSet()
+	b.Insert(1)
+	huge := strconv.FormatUint(uint64(MaxBitSetElement)+1, 10)
+	if err := (&SQL[uint]{Set: b}).Scan("{" + huge + "}"); !errors.Is(err, ErrTooLarge) || !slices.Equal(b.ToSlice(), []uint{1}) {
+		t.Errorf("Scan() of a huge BitSet element = %v, %v, want ErrTooLarge and the set unchanged", b, err)
+	}
+}
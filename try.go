@@ -0,0 +1,90 @@
+package set
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// The Try functions perform a binary operation like the method of the same
+// name, but report failures as errors instead of panicking. The
+// implementations in this package work with every other implementation, but a
+// Set from another package may require its argument to be of its own type and
+// panic with a failed type assertion otherwise. The Try functions return an
+// error wrapping ErrIncompatibleImplementation if the operation fails with a
+// type assertion on the dynamic type of the other set, and one wrapping
+// ErrNilSet if either set is nil. Other panics are not recovered.
+
+// TryUnion is like s.Union(other), but returns an error instead of panicking
+// if the two sets cannot be combined.
+func TryUnion[T comparable](s, other Set[T]) (Set[T], error) {
+	return try("union", s, other, func() Set[T] { return s.Union(other) })
+}
+
+// TryIntersection is like s.Intersection(other), but returns an error instead
+// of panicking if the two sets cannot be combined.
+func TryIntersection[T comparable](s, other Set[T]) (Set[T], error) {
+	return try("intersection", s, other, func() Set[T] { return s.Intersection(other) })
+}
+
+// TryDifference is like s.Difference(other), but returns an error instead of
+// panicking if the two sets cannot be combined.
+func TryDifference[T comparable](s, other Set[T]) (Set[T], error) {
+	return try("difference", s, other, func() Set[T] { return s.Difference(other) })
+}
+
+// TrySymmetricDifference is like s.SymmetricDifference(other), but returns an
+// error instead of panicking if the two sets cannot be combined.
+func TrySymmetricDifference[T comparable](s, other Set[T]) (Set[T], error) {
+	return try("symmetric difference", s, other, func() Set[T] { return s.SymmetricDifference(other) })
+}
+
+// TryEquals is like s.Equals(other), but returns an error instead of panicking
+// if the two sets cannot be compared.
+func TryEquals[T comparable](s, other Set[T]) (bool, error) {
+	return try("equality", s, other, func() bool { return s.Equals(other) })
+}
+
+// TryIsSubsetOf is like s.IsSubsetOf(other), but returns an error instead of
+// panicking if the two sets cannot be compared.
+func TryIsSubsetOf[T comparable](s, other Set[T]) (bool, error) {
+	return try("subset test", s, other, func() bool { return s.IsSubsetOf(other) })
+}
+
+// try calls op and converts a failed type assertion of other inside it into
+// an error wrapping ErrIncompatibleImplementation.
+func try[T comparable, R any](name string, s, other Set[T], op func() R) (result R, err error) {
+	if isNil(s) || isNil(other) {
+		return result, fmt.Errorf("%w: %s of %T and %T", ErrNilSet, name, s, other)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			assertion, ok := r.(*runtime.TypeAssertionError)
+			if !ok || !assertsType(assertion, reflect.TypeOf(other)) {
+				panic(r)
+			}
+			err = fmt.Errorf("%w: %s of %T and %T: %w", ErrIncompatibleImplementation, name, s, other, assertion)
+		}
+	}()
+	return op(), nil
+}
+
+// assertsType reports whether assertion failed on a value of dynamic type t,
+// as when a Set asserts its argument to a different concrete type or to an
+// interface that t does not implement.
+func assertsType(assertion *runtime.TypeAssertionError, t reflect.Type) bool {
+	msg := assertion.Error()
+	return strings.Contains(msg, " is "+t.String()+", not ") ||
+		strings.HasPrefix(msg, "interface conversion: "+t.String()+" is not ")
+}
+
+// isNil reports whether s is nil or holds a nil pointer.
+func isNil[T comparable](s Set[T]) bool {
+	if s == nil {
+		return true
+	}
+	v := reflect.ValueOf(s)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
@@ -0,0 +1,135 @@
+package set
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+// strictSet is a Set from "another package" whose binary operations only
+// accept sets of its own type, as some third-party implementations do.
+type strictSet struct {
+	*mockSet[int]
+}
+
+func (s strictSet) Union(other Set[int]) Set[int] {
+	return strictSet{s.mockSet.Union(other.(strictSet).mockSet).(*mockSet[int])}
+}
+
+func (s strictSet) Equals(other Set[int]) bool {
+	return s.mockSet.Equals(other.(strictSet).mockSet)
+}
+
+// adapterSet wraps a Set of this package, as adapters in other packages do.
+// Its binary operations accept any Set.
+type adapterSet struct {
+	Set[int]
+}
+
+func TestTryOperations(t *testing.T) {
+	a := NewHashSet[int]()
+	a.InsertAll(1, 2, 3)
+	b := newMockSet(3, 4)
+	strict := strictSet{newMockSet(1, 2)}
+	adapter := adapterSet{NewHashSet[int]()}
+	adapter.InsertAll(1, 2)
+	var nilSet *SortedSet[int]
+
+	tests := []struct {
+		name     string
+		op       func() (Set[int], error)
+		expected []int
+		err      error
+	}{
+		{"TryUnion", func() (Set[int], error) { return TryUnion(a, b) }, []int{1, 2, 3, 4}, nil},
+		{"TryIntersection", func() (Set[int], error) { return TryIntersection(a, b) }, []int{3}, nil},
+		{"TryDifference", func() (Set[int], error) { return TryDifference(a, b) }, []int{1, 2}, nil},
+		{"TrySymmetricDifference", func() (Set[int], error) { return TrySymmetricDifference(a, b) }, []int{1, 2, 4}, nil},
+		{"TryUnion of strict sets", func() (Set[int], error) { return TryUnion[int](strict, strict) }, []int{1, 2}, nil},
+		{"TryUnion of adapter and set", func() (Set[int], error) { return TryUnion[int](adapter, a) }, []int{1, 2, 3}, nil},
+		{"TryUnion of set and adapter", func() (Set[int], error) { return TryUnion[int](a, adapter) }, []int{1, 2, 3}, nil},
+		{"TryDifference of foreign set and set", func() (Set[int], error) { return TryDifference[int](b, a) }, []int{4}, nil},
+		{"TryUnion with incompatible set", func() (Set[int], error) { return TryUnion[int](strict, a) }, nil, ErrIncompatibleImplementation},
+		{"TryIntersection with nil set", func() (Set[int], error) { return TryIntersection(a, nil) }, nil, ErrNilSet},
+		{"TryDifference of nil pointer", func() (Set[int], error) { return TryDifference[int](nilSet, a) }, nil, ErrNilSet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.op()
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("%s() error = %v, want %v", tt.name, err, tt.err)
+			}
+			if tt.err != nil {
+				if result != nil {
+					t.Errorf("%s() = %v, want nil on error", tt.name, result)
+				}
+				return
+			}
+			if !newMockSet(tt.expected...).Equals(result) {
+				t.Errorf("%s() = %v, want %v", tt.name, result, tt.expected)
+			}
+		})
+	}
+
+	if equal, err := TryEquals[int](strict, strictSet{newMockSet(2, 1)}); err != nil || !equal {
+		t.Errorf("TryEquals() = %v, %v, want true, nil", equal, err)
+	}
+	if _, err := TryEquals[int](strict, b); !errors.Is(err, ErrIncompatibleImplementation) {
+		t.Errorf("TryEquals() error = %v, want ErrIncompatibleImplementation", err)
+	}
+	if subset, err := TryIsSubsetOf[int](a, b); err != nil || subset {
+		t.Errorf("TryIsSubsetOf() = %v, %v, want false, nil", subset, err)
+	}
+}
+
+func TestTryOperationsRepanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want the original panic value", r)
+		}
+	}()
+	_, _ = try("union", NewHashSet[int](), NewHashSet[int](), func() Set[int] { panic("boom") })
+	t.Error("try() swallowed a panic")
+}
+
+func TestTryOperationsRepanicTypeAssertion(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(*runtime.TypeAssertionError); !ok {
+			t.Error("try() did not propagate a type assertion failure inside the operation")
+		}
+	}()
+	var elem any = "x"
+	_, _ = try("union", NewHashSet[int](), NewHashSet[int](), func() Set[int] { return newMockSet(elem.(int)) })
+	t.Error("try() swallowed a failed type assertion of the operation")
+}
+
+func TestCartesianProductWithLimit(t *testing.T) {
+	a := newMockSet(1, 2, 3)
+	b := newMockSet("x", "y")
+
+	tests := []struct {
+		name  string
+		limit int
+		err   error
+	}{
+		{"within limit", 6, nil},
+		{"above limit", 5, ErrTooLarge},
+		{"negative limit", -1, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product, err := CartesianProductWithLimit[int, string](a, b, tt.limit)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("CartesianProductWithLimit() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && product.Cardinality() != 6 {
+				t.Errorf("CartesianProductWithLimit() has %d pairs, want 6", product.Cardinality())
+			}
+		})
+	}
+
+	if product, err := CartesianProductWithLimit[int, string](a, newMockSet[string](), 0); err != nil || !product.IsEmpty() {
+		t.Errorf("CartesianProductWithLimit() with an empty set = %v, %v, want {}, nil", product, err)
+	}
+}